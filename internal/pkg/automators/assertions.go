@@ -0,0 +1,59 @@
+package automators
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/expr"
+)
+
+// variables available to assertions, latency is in milliseconds
+var assertionVariables = []string{"status", "headers", "body", "latency"}
+
+type headerValues http.Header
+
+func (h headerValues) Get(key string) (any, bool) {
+	values := http.Header(h).Values(key)
+	if len(values) == 0 {
+		return nil, false
+	}
+	return strings.Join(values, ", "), true
+}
+
+func validateAssertions(assertions []string) error {
+	_, err := compileAssertions(assertions)
+	return err
+}
+
+func compileAssertions(assertions []string) ([]*expr.Program, error) {
+	programs := make([]*expr.Program, 0, len(assertions))
+	for i, assertion := range assertions {
+		program, err := expr.Compile(assertion, assertionVariables...)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assertion %d: %w", i+1, err)
+		}
+		programs = append(programs, program)
+	}
+	return programs, nil
+}
+
+func assertionEnv(response *http.Response, body any, latency time.Duration) map[string]any {
+	return map[string]any{
+		"status":  response.StatusCode,
+		"headers": headerValues(response.Header),
+		"body":    body,
+		"latency": float64(latency) / float64(time.Millisecond),
+	}
+}
+
+// evaluateAssertions stops at the first assertion that does not hold.
+func evaluateAssertions(programs []*expr.Program, env map[string]any) error {
+	for i, program := range programs {
+		if err := program.Eval(env); err != nil {
+			return fmt.Errorf("assertion %d failed: %w", i+1, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package automators_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func TestHTTPChecker_Assertions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Region", "eu-west-1")
+		w.Write([]byte(`{"status":"degraded","items":[{"id":1},{"id":2}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name        string
+		assertions  []string
+		wantVerdict automators.Verdict
+		wantError   string
+	}{
+		{
+			name:        "all assertions hold",
+			assertions:  []string{`status == 200`, `len(body.items) > 0 && body.items[1].id == 2`, `headers["x-region"] == "eu-west-1"`, `latency < 2000`},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "failing sub-expression reported",
+			assertions:  []string{`status == 200`, `len(body.items) > 0 && body.status == 'ok'`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   `assertion 2 failed: body.status == 'ok' is false (body.status = "degraded")`,
+		},
+		{
+			name:        "evaluation error reported",
+			assertions:  []string{`body.items[5].id == 1`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   `assertion 1 failed: error evaluating body.items[5]: index 5 out of range for array of length 2`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			task := automators.Task{URL: server.URL, Assertions: tt.assertions}
+			if err := automators.NewHTTPChecker().Validate(task); err != nil {
+				t.Fatalf("httpChecker.Validate() error = %v", err)
+			}
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: task})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("httpChecker.Execute() verdict = %s, want %s (error %q)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if !strings.Contains(got.Error, tt.wantError) {
+				t.Errorf("httpChecker.Execute() error = %q, want %q", got.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -4,52 +4,180 @@ import (
 	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
-	"net/http"
-	"os"
-	"os/signal"
-	"syscall"
+	"sync"
 	"time"
 
 	"github.com/go-co-op/gocron"
 	"github.com/google/uuid"
 	"github.com/jboakyedonkor/ping-app/internal/pkg/cache"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/hashring"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/robfig/cron/v3"
 	"go.uber.org/zap"
 )
 
 type Automator struct {
-	cache      Cacher
-	secretKey  []byte
-	jobSetName string
-	scheduler  *gocron.Scheduler
-	logger     *zap.SugaredLogger
+	cache             Cacher
+	secretKey         []byte
+	jobSetName        string
+	replicaSetName    string
+	eventChannel      string
+	contentChannel    string
+	replicaID         string
+	reconcileInterval time.Duration
+	scheduler         *gocron.Scheduler
+	logger            *zap.SugaredLogger
+
+	ringMu sync.RWMutex
+	ring   *hashring.Ring
+
+	jobsMu          sync.Mutex
+	scheduledHashes map[string]string
+
+	reconcileMu   sync.RWMutex
+	lastReconcile *ReconcileResult
+
+	lifecycleMu sync.Mutex
+	cancel      context.CancelFunc
+	stopped     bool
+	loops       sync.WaitGroup
+
+	checkers            *CheckerRegistry
+	transports          *TransportPool
+	metricsRegisterer   prometheus.Registerer
+	metrics             *checkMetrics
+	proxy               *ProxyConfig
+	runCtx              context.Context
+	cancelRuns          context.CancelFunc
+	runsMu              sync.Mutex
+	draining            bool
+	inFlight            sync.WaitGroup
+	shutdownGracePeriod time.Duration
 }
 
+type Option func(*Automator)
+
 type Cacher interface {
 	InsertData(ctx context.Context, key, data string) error
 	GetData(ctx context.Context, key string) (string, error)
+	LookupData(ctx context.Context, key string) (string, bool, error)
 	DeleteData(ctx context.Context, key string) error
 	GetSet(ctx context.Context, key string) (map[string]struct{}, error)
 	DeleteSet(ctx context.Context, key string) error
 	DeleteFromSet(ctx context.Context, setName string, keys ...string) error
 	UpdateSet(ctx context.Context, setName string, keys ...string) error
+	UpdateSortedSet(ctx context.Context, setName, member string, score float64) error
+	GetSortedSetByScore(ctx context.Context, setName string, min, max float64) ([]string, error)
+	DeleteFromSortedSet(ctx context.Context, setName string, members ...string) error
+	DeleteFromSortedSetByScore(ctx context.Context, setName string, min, max float64) error
+	PushToList(ctx context.Context, key, data string, maxLen int64) error
+	GetList(ctx context.Context, key string, start, stop int64) ([]string, error)
+	Publish(ctx context.Context, channel, message string) error
+	Subscribe(ctx context.Context, channel string) (<-chan string, error)
 }
 
-type jobFunc func(config *JobConfig, logger *zap.SugaredLogger) (any, error)
-
 const (
-	reconcileTickerDuration = 10 * time.Second
+	reconcileTickerDuration    = 10 * time.Second
+	defaultCheckTimeout        = 30 * time.Second
+	defaultShutdownGracePeriod = 20 * time.Second
+	runResultHistory           = 100
+	// caps each entry of the result history, results over it are stored as
+	// a summary without bodies, snapshots or check details
+	maxStoredResultBytes = 64 << 10
+	maxStoredErrorBytes  = 1 << 10
 )
 
-func NewAutomator(cache Cacher, secretKey []byte, scheduler *gocron.Scheduler, logger *zap.SugaredLogger) *Automator {
-	return &Automator{
-		cache:      cache,
-		secretKey:  secretKey,
-		scheduler:  scheduler,
-		logger:     logger,
-		jobSetName: "jobs_set",
+func NewAutomator(cache Cacher, secretKey []byte, scheduler *gocron.Scheduler, logger *zap.SugaredLogger, opts ...Option) *Automator {
+	a := &Automator{
+		cache:             cache,
+		secretKey:         secretKey,
+		scheduler:         scheduler,
+		logger:            logger,
+		jobSetName:        "jobs_set",
+		replicaSetName:    "replicas_set",
+		eventChannel:      "jobs_events",
+		contentChannel:    "content_events",
+		replicaID:         uuid.NewString(),
+		reconcileInterval: reconcileTickerDuration,
+		scheduledHashes:   make(map[string]string),
+		checkers:          NewCheckerRegistry(),
+		transports:        NewTransportPool(TransportSettings{}),
+		metricsRegisterer: prometheus.NewRegistry(),
+
+		shutdownGracePeriod: defaultShutdownGracePeriod,
+	}
+	a.runCtx, a.cancelRuns = context.WithCancel(context.Background())
+	a.checkers.Register(CheckTypeHTTP, NewHTTPCheckerWithTransports(a.transports))
+	a.checkers.Register(CheckTypeTCP, NewTCPChecker())
+	a.checkers.Register(CheckTypeDNS, NewDNSChecker())
+	a.checkers.Register(CheckTypeTLS, NewTLSChecker(nil))
+	a.checkers.Register(CheckTypeGRPC, NewGRPCChecker())
+	a.checkers.Register(CheckTypeWebSocket, NewWebSocketChecker())
+	a.checkers.Register(CheckTypeHeartbeat, NewHeartbeatChecker(cache))
+	a.checkers.Register(CheckTypeScenario, NewScenarioCheckerWithTransports(a.transports))
+
+	for _, opt := range opts {
+		opt(a)
+	}
+	a.metrics = newCheckMetrics(a.metricsRegisterer)
+	return a
+}
+
+func WithReplicaID(replicaID string) Option {
+	return func(a *Automator) {
+		if replicaID != "" {
+			a.replicaID = replicaID
+		}
+	}
+}
+
+func WithShutdownGracePeriod(gracePeriod time.Duration) Option {
+	return func(a *Automator) {
+		if gracePeriod > 0 {
+			a.shutdownGracePeriod = gracePeriod
+		}
+	}
+}
+
+func WithChecker(checkType string, checker Checker) Option {
+	return func(a *Automator) {
+		a.checkers.Register(checkType, checker)
+	}
+}
+
+func WithTransportSettings(settings TransportSettings) Option {
+	return func(a *Automator) {
+		a.transports.mu.Lock()
+		defer a.transports.mu.Unlock()
+		a.transports.settings = settings
+	}
+}
+
+func WithMetricsRegisterer(registerer prometheus.Registerer) Option {
+	return func(a *Automator) {
+		if registerer != nil {
+			a.metricsRegisterer = registerer
+		}
+	}
+}
+
+func WithProxy(proxyURL string) Option {
+	return func(a *Automator) {
+		if proxyURL != "" {
+			a.proxy = &ProxyConfig{URL: proxyURL}
+		}
+	}
+}
+
+func WithReconcileInterval(interval time.Duration) Option {
+	return func(a *Automator) {
+		if interval > 0 {
+			a.reconcileInterval = interval
+		}
 	}
 }
 
@@ -58,6 +186,15 @@ func (a *Automator) CreateNewJob(ctx context.Context, config JobConfig) (string,
 	UUID := uuid.New()
 
 	config.UID = UUID
+	if config.Type == CheckTypeHeartbeat && config.Task.Heartbeat != nil && config.Task.Heartbeat.Token == "" {
+		token, err := newHeartbeatToken()
+		if err != nil {
+			logger.Error(err)
+			return "", err
+		}
+		config.Task.Heartbeat.Token = token
+	}
+
 	bytes, err := json.Marshal(config)
 	if err != nil {
 		return "", fmt.Errorf("error marshalling job config: %w", err)
@@ -72,15 +209,35 @@ func (a *Automator) CreateNewJob(ctx context.Context, config JobConfig) (string,
 		return "", err
 	}
 
-	_, err = a.scheduler.CronWithSeconds(config.CronExpression).Tag(config.UID.String()).Do(templateJobFunc, &config, a.logger)
-	if err != nil {
-		err := fmt.Errorf("error scheduling job: %w", err)
+	if err := a.validateJob(config); err != nil {
 		logger.Error(err)
 		return "", err
 	}
 
+	// a heartbeat run reports the job down until its state exists
+	if config.Type == CheckTypeHeartbeat {
+		if err := a.registerHeartbeat(ctx, &config); err != nil {
+			logger.Error(err)
+			return "", err
+		}
+	}
+
+	// hold the lock until the job is in the job set so a concurrent reconcile
+	// does not treat the freshly scheduled job as an orphan
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+
+	owned := a.ownsJob(config.UID.String())
+	if owned {
+		if err := a.scheduleJob(&config); err != nil {
+			err := fmt.Errorf("error scheduling job: %w", err)
+			logger.Error(err)
+			return "", err
+		}
+	}
+
 	if err := a.cache.InsertData(ctx, config.UID.String(), encryptedJob); err != nil {
-		if err := a.scheduler.RemoveByTag(config.UID.String()); err != nil {
+		if err := a.unscheduleJob(config.UID.String()); err != nil {
 
 			logger.Errorf("error deleting job after error insert job config into cache: %w", err)
 			return "", fmt.Errorf("error removing job: %w", err)
@@ -94,123 +251,234 @@ func (a *Automator) CreateNewJob(ctx context.Context, config JobConfig) (string,
 		return "", fmt.Errorf("error updating job set: %w", err)
 	}
 
-	logger.Debugw("created new job", "jobUID", config.UID.String())
+	a.publishJobEvent(ctx, JobCreated, config.UID.String())
+
+	logger.Debugw("created new job", "jobUID", config.UID.String(), "scheduled_locally", owned)
 	return config.UID.String(), nil
 }
 
-func (a *Automator) DeleteJob(ctx context.Context, jobUID uuid.UUID) error {
+func (a *Automator) UpdateJob(ctx context.Context, jobUID uuid.UUID, config JobConfig) error {
 	logger := a.logger.With("context", ctx)
 	jobID := jobUID.String()
-	if err := a.scheduler.RemoveByTag(jobID); err != nil {
-		err := fmt.Errorf("error removing job from scheduler: %w", err)
+
+	data, err := a.cache.GetData(ctx, jobID)
+	if err != nil {
+		if _, ok := err.(*cache.NotFoundError); ok {
+			return err
+		}
+		err := fmt.Errorf("error retrieving job data: %w", err)
 		logger.Error(err)
 		return err
 	}
 
-	if err := a.cache.DeleteData(ctx, jobID); err != nil {
-		err := fmt.Errorf("error removing job config cache: %w", err)
+	var previousToken string
+	if existing, err := DecryptJobInfo(a.secretKey, data); err == nil {
+		previousToken = heartbeatToken(existing)
+	}
+
+	if err := a.validateJob(config); err != nil {
 		logger.Error(err)
 		return err
 	}
 
-	if err := a.cache.DeleteFromSet(ctx, a.jobSetName, jobID); err != nil {
-		err := fmt.Errorf("error removing job config cache set: %w", err)
+	config.UID = jobUID
+	if config.Type == CheckTypeHeartbeat && config.Task.Heartbeat.Token == "" {
+		config.Task.Heartbeat.Token = previousToken
+		if previousToken == "" {
+			token, err := newHeartbeatToken()
+			if err != nil {
+				logger.Error(err)
+				return err
+			}
+			config.Task.Heartbeat.Token = token
+		}
+	}
+
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("error marshalling job config: %w", err)
+	}
+
+	encryptedJob, err := EncryptJobInfo(a.secretKey, string(bytes))
+	if err != nil {
+		err := fmt.Errorf("error encrypting job config: %w", err)
+		logger.Error(err)
+		return err
+	}
+
+	if err := a.cache.InsertData(ctx, jobID, encryptedJob); err != nil {
+		err := fmt.Errorf("error updating job in cache: %w", err)
 		logger.Error(err)
 		return err
 	}
+
+	if config.Type != CheckTypeHeartbeat && previousToken != "" {
+		if err := a.unregisterHeartbeat(ctx, jobID, previousToken); err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	if config.Type == CheckTypeHeartbeat {
+		if previousToken != "" && previousToken != config.Task.Heartbeat.Token {
+			if err := a.cache.DeleteData(ctx, heartbeatTokenKey(previousToken)); err != nil {
+				err := fmt.Errorf("error removing heartbeat token: %w", err)
+				logger.Error(err)
+				return err
+			}
+		}
+
+		if err := a.registerHeartbeat(ctx, &config); err != nil {
+			logger.Error(err)
+			return err
+		}
+	}
+
+	if a.ownsJob(jobID) {
+		a.jobsMu.Lock()
+		err := a.unscheduleJob(jobID)
+		if err == nil {
+			err = a.scheduleJob(&config)
+		}
+		a.jobsMu.Unlock()
+		if err != nil {
+			err := fmt.Errorf("error rescheduling job: %w", err)
+			logger.Error(err)
+			return err
+		}
+	}
+
+	a.publishJobEvent(ctx, JobUpdated, jobID)
+
+	logger.Debugw("updated job", "jobUID", jobID)
 	return nil
 }
 
-func (a *Automator) GetJob(ctx context.Context, jobUID uuid.UUID) (*JobConfig, error) {
-	logger := a.logger.With("context", ctx)
-	data, err := a.cache.GetData(ctx, jobUID.String())
+func (a *Automator) scheduleJob(config *JobConfig) error {
+	hash, err := configHash(config)
 	if err != nil {
-		if _, ok := err.(*cache.NotFoundError); ok {
-			return nil, err
-		}
-		err := fmt.Errorf("error retrieving job data: %w", err)
-		logger.Error(err)
-		return nil, err
+		return err
 	}
 
-	config, err := DecryptJobInfo(a.secretKey, data)
-	if err != nil {
-		err := fmt.Errorf("error decrypting job data: %w", err)
-		logger.Error(err)
-		return nil, err
+	if config.assertions, err = compileAssertions(config.Task.Assertions); err != nil {
+		return err
 	}
 
-	return config, nil
-}
+	if config.Task.ContentChange != nil {
+		if config.ignorePatterns, err = compileIgnorePatterns(config.Task.ContentChange.IgnorePatterns); err != nil {
+			return err
+		}
+	}
 
-func (a *Automator) ReconcileJobs() {
-	quit := make(chan os.Signal, 1)
+	if _, err := a.scheduler.CronWithSeconds(config.CronExpression).Tag(config.UID.String()).Do(a.runJob, config); err != nil {
+		return err
+	}
 
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	a.scheduledHashes[config.UID.String()] = hash
+	return nil
+}
 
-	ticker := time.NewTicker(reconcileTickerDuration)
+func (a *Automator) validateJob(config JobConfig) error {
+	if err := a.validateCronExpression(config.CronExpression); err != nil {
+		return &ValidationError{Err: err}
+	}
 
-	isUp := true
-	for isUp {
-		select {
+	checker, err := a.checkers.Get(config.Type)
+	if err != nil {
+		return &ValidationError{Err: err}
+	}
 
-		case <-ticker.C:
-			a.logger.Info("start reconcile ticker jobs")
-			go a.reconcileJobs()
+	if err := checker.Validate(config.Task); err != nil {
+		return &ValidationError{Err: err}
+	}
 
-		case <-quit:
-			ticker.Stop()
-			a.scheduler.Stop()
-			a.logger.Info("ticker and scheduler stopped")
-			isUp = false
+	if config.Task.Proxy != nil {
+		if err := validateProxy(config.Task.Proxy); err != nil {
+			return &ValidationError{Err: err}
 		}
 	}
+	return nil
+}
+
+func (a *Automator) validateCronExpression(cronExpression string) error {
+	_, err := parseCronExpression(cronExpression)
+	return err
+}
 
-	a.logger.Info("reconciling of jobs stopped")
+func parseCronExpression(cronExpression string) (cron.Schedule, error) {
+	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+	schedule, err := parser.Parse(cronExpression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", gocron.ErrCronParseFailure, err)
+	}
+	return schedule, nil
 }
 
-func (a *Automator) reconcileJobs() {
+func (a *Automator) DeleteJob(ctx context.Context, jobUID uuid.UUID) error {
+	logger := a.logger.With("context", ctx)
+	jobID := jobUID.String()
 
-	ctx := context.Background()
+	var previousToken string
+	if existing, err := a.GetJob(ctx, jobUID); err == nil {
+		previousToken = heartbeatToken(existing)
+	}
 
-	jobSet, err := a.cache.GetSet(ctx, a.jobSetName)
+	a.jobsMu.Lock()
+	err := a.unscheduleJob(jobID)
+	a.jobsMu.Unlock()
 	if err != nil {
-		a.logger.Errorf("error getting job set: ", err)
-		return
+		logger.Error(err)
+		return err
 	}
 
-	tags := make(map[string]struct{}, 0)
+	if err := a.cache.DeleteData(ctx, jobID); err != nil {
+		err := fmt.Errorf("error removing job config cache: %w", err)
+		logger.Error(err)
+		return err
+	}
 
-	for _, job := range a.scheduler.Jobs() {
-		for _, tag := range job.Tags() {
-			tags[tag] = struct{}{}
-		}
+	if err := a.cache.DeleteFromSet(ctx, a.jobSetName, jobID); err != nil {
+		err := fmt.Errorf("error removing job config cache set: %w", err)
+		logger.Error(err)
+		return err
 	}
 
-	jobUUIDs := make([]string, 0)
+	if err := a.cache.DeleteData(ctx, runResultsKey(jobID)); err != nil {
+		logger.Errorf("error removing job results: %s", err)
+	}
 
-	for key := range jobSet {
-		if _, ok := tags[key]; !ok {
-			jobUUIDs = append(jobUUIDs, key)
-		}
+	if err := a.unregisterHeartbeat(ctx, jobID, previousToken); err != nil {
+		logger.Error(err)
 	}
-	if len(jobUUIDs) > 0 {
-		a.logger.Infow("missing jobs", "ids", jobUUIDs)
+
+	if err := a.cache.DeleteData(ctx, contentStateKey(jobID)); err != nil {
+		logger.Errorf("error removing content state: %s", err)
 	}
 
-	for _, uuid := range jobUUIDs {
-		data, err := a.cache.GetData(ctx, uuid)
-		if err != nil {
-			continue
-		}
+	a.publishJobEvent(ctx, JobDeleted, jobID)
+	return nil
+}
 
-		config, err := DecryptJobInfo(a.secretKey, data)
-		if err != nil {
-			continue
+func (a *Automator) GetJob(ctx context.Context, jobUID uuid.UUID) (*JobConfig, error) {
+	logger := a.logger.With("context", ctx)
+	data, err := a.cache.GetData(ctx, jobUID.String())
+	if err != nil {
+		if _, ok := err.(*cache.NotFoundError); ok {
+			return nil, err
 		}
+		err := fmt.Errorf("error retrieving job data: %w", err)
+		logger.Error(err)
+		return nil, err
+	}
 
-		a.scheduler.CronWithSeconds(config.CronExpression).Tag(config.UID.String()).Do(templateJobFunc, config, a.logger)
+	config, err := DecryptJobInfo(a.secretKey, data)
+	if err != nil {
+		err := fmt.Errorf("error decrypting job data: %w", err)
+		logger.Error(err)
+		return nil, err
 	}
+
+	return config, nil
 }
 
 func (a *Automator) GetRunningJobs(ctx context.Context) ([]*JobConfig, error) {
@@ -246,57 +514,6 @@ func (a *Automator) GetRunningJobs(ctx context.Context) ([]*JobConfig, error) {
 	return jobConfigs, nil
 }
 
-func templateJobFunc(config *JobConfig, joblogger *zap.SugaredLogger) (any, error) {
-
-	logger := joblogger.With("job_id", config.UID)
-	start := time.Now()
-	ctx := context.Background()
-
-	client := http.Client{}
-
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, config.Task.URL, nil)
-	if err != nil {
-		err := fmt.Errorf("error creating request: %w", err)
-		logger.Error(err)
-		return nil, err
-	}
-
-	scheme := config.Task.AuthHeader.Scheme
-	auth := ""
-	if scheme != "" {
-		if scheme != "Bearer" && scheme != "Basic" && scheme != "Digest" {
-			err := fmt.Errorf("invalid scheme")
-			logger.Error(err)
-			return nil, err
-		}
-		auth = fmt.Sprintf("%s %s", scheme, config.Task.AuthHeader.Parameters)
-	}
-
-	request.Header.Add("Authorization", auth)
-	response, err := client.Do(request)
-	if err != nil {
-		err := fmt.Errorf("error making request: %w", err)
-		logger.Error(err)
-		return nil, err
-	}
-	defer response.Body.Close()
-
-	var r any
-
-	if response.Body != nil && response.Header.Get("Content-Type") == "application/json" {
-		err = json.NewDecoder(response.Body).Decode(&r)
-		if err != nil {
-			err := fmt.Errorf("error decoding response: %w", err)
-			logger.Error(err)
-			return nil, err
-		}
-	}
-	jobDuration := time.Since(start)
-
-	logger.Infow("job completed", "body", r, "status_code", response.StatusCode, "duration_ns", jobDuration.Nanoseconds())
-	return r, nil
-}
-
 func EncryptJobInfo(key []byte, jobInfo string) (string, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -308,7 +525,12 @@ func EncryptJobInfo(key []byte, jobInfo string) (string, error) {
 		return "", fmt.Errorf("error creating GCM: %w", err)
 	}
 
+	// a GCM nonce must never repeat under the same key, configs hold secrets
+	// and are re-encrypted on every update
 	nonce := make([]byte, aesGCM.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error generating nonce: %w", err)
+	}
 
 	cipherText := aesGCM.Seal(nonce, nonce, []byte(jobInfo), nil)
 
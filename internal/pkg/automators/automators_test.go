@@ -7,7 +7,10 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -170,6 +173,14 @@ func TestEncryptJobInfo(t *testing.T) {
 			if !reflect.DeepEqual(actualConfig, tt.want) {
 				t.Errorf("EncryptJobInfo() = %v, want %v", actualConfig, tt.want)
 			}
+
+			again, err := automators.EncryptJobInfo(tt.args.key, tt.args.jobInfo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again[:2*nonceSize] == got[:2*nonceSize] {
+				t.Errorf("EncryptJobInfo() reused nonce %s", got[:2*nonceSize])
+			}
 		})
 	}
 }
@@ -380,3 +391,479 @@ func TestAutomator_DeleteJob(t *testing.T) {
 		})
 	}
 }
+
+func TestAutomator_UpdateJob(t *testing.T) {
+	t.Parallel()
+	type args struct {
+		jobUID uuid.UUID
+		config automators.JobConfig
+	}
+	tests := []struct {
+		name    string
+		cache   *mock.CacherStore
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "successful update",
+			cache: &mock.CacherStore{
+				Cache: map[string]string{
+					"a8c14eb0-0fba-4b75-a461-e4d380317ab7": "job-config",
+				},
+				SetName: "jobs_set",
+			},
+			args: args{
+				jobUID: uuid.MustParse("a8c14eb0-0fba-4b75-a461-e4d380317ab7"),
+				config: automators.JobConfig{
+					CronExpression: "*/5 * * * * *",
+					Task: automators.Task{
+						URL:     "http://127.0.0.1/ping",
+						Timeout: time.Minute,
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "job not found",
+			cache: &mock.CacherStore{
+				Cache:   make(map[string]string),
+				SetName: "jobs_set",
+			},
+			args: args{
+				jobUID: uuid.MustParse("a8c14eb0-0fba-4b75-a461-e4d380317ab7"),
+				config: automators.JobConfig{
+					CronExpression: "*/5 * * * * *",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid cron expression",
+			cache: &mock.CacherStore{
+				Cache: map[string]string{
+					"a8c14eb0-0fba-4b75-a461-e4d380317ab7": "job-config",
+				},
+				SetName: "jobs_set",
+			},
+			args: args{
+				jobUID: uuid.MustParse("a8c14eb0-0fba-4b75-a461-e4d380317ab7"),
+				config: automators.JobConfig{
+					CronExpression: "* * * rv *",
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			secretKey := []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ")
+			scheduler := gocron.NewScheduler(time.Local)
+			ctx := context.Background()
+
+			a := automators.NewAutomator(tt.cache, secretKey, scheduler, zap.NewExample().Sugar())
+
+			err := a.UpdateJob(ctx, tt.args.jobUID, tt.args.config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Automator.UpdateJob() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			config, err := automators.DecryptJobInfo(secretKey, tt.cache.Cache[tt.args.jobUID.String()])
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.CronExpression != tt.args.config.CronExpression || config.UID != tt.args.jobUID {
+				t.Errorf("Automator.UpdateJob() stored %v, want %v", config, tt.args.config)
+			}
+
+			if _, err := scheduler.FindJobsByTag(tt.args.jobUID.String()); err != nil {
+				t.Errorf("Automator.UpdateJob() did not reschedule job: %v", err)
+			}
+
+			if len(tt.cache.Published) != 1 {
+				t.Fatalf("Automator.UpdateJob() published %d events, want 1", len(tt.cache.Published))
+			}
+			var event automators.JobEvent
+			if err := json.Unmarshal([]byte(tt.cache.Published[0]), &event); err != nil {
+				t.Fatal(err)
+			}
+			if event.Type != automators.JobUpdated || event.UID != tt.args.jobUID.String() {
+				t.Errorf("Automator.UpdateJob() published %v", event)
+			}
+		})
+	}
+}
+
+func TestAutomator_Reconcile(t *testing.T) {
+	t.Parallel()
+
+	secretKey := []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ")
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	scheduler := gocron.NewScheduler(time.Local)
+	ctx := context.Background()
+
+	a := automators.NewAutomator(store, secretKey, scheduler, zap.NewExample().Sugar())
+
+	changedUID, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "0 0 * * * *",
+		Task:           automators.Task{URL: "http://127.0.0.1/ping"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encryptConfig := func(config automators.JobConfig) string {
+		bytes, err := json.Marshal(config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := automators.EncryptJobInfo(secretKey, string(bytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	store.Cache[changedUID] = encryptConfig(automators.JobConfig{
+		CronExpression: "0 */5 * * * *",
+		UID:            uuid.MustParse(changedUID),
+		Task:           automators.Task{URL: "http://127.0.0.1/ping"},
+	})
+
+	missingUID := "a8c14eb0-0fba-4b75-a461-e4d380317ab7"
+	store.Cache[missingUID] = encryptConfig(automators.JobConfig{
+		CronExpression: "0 0 * * * *",
+		UID:            uuid.MustParse(missingUID),
+		Task:           automators.Task{URL: "http://127.0.0.1/ping"},
+	})
+	store.CacheSet[missingUID] = struct{}{}
+
+	orphanUID := "0b1f6a6e-7c55-4c1a-9d0c-3e0f1b5c2d11"
+	if _, err := scheduler.Tag(orphanUID).Every("1h").Do(func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	got := a.Reconcile(ctx)
+
+	if len(got.Errors) > 0 {
+		t.Fatalf("Automator.Reconcile() errors = %v", got.Errors)
+	}
+	if !reflect.DeepEqual(got.Added, []string{missingUID}) {
+		t.Errorf("Automator.Reconcile() added = %v, want %v", got.Added, []string{missingUID})
+	}
+	if !reflect.DeepEqual(got.Removed, []string{orphanUID}) {
+		t.Errorf("Automator.Reconcile() removed = %v, want %v", got.Removed, []string{orphanUID})
+	}
+	if !reflect.DeepEqual(got.Rescheduled, []string{changedUID}) {
+		t.Errorf("Automator.Reconcile() rescheduled = %v, want %v", got.Rescheduled, []string{changedUID})
+	}
+
+	if _, err := scheduler.FindJobsByTag(orphanUID); err == nil {
+		t.Errorf("Automator.Reconcile() did not remove orphaned job")
+	}
+
+	if !reflect.DeepEqual(a.LastReconcile(), got) {
+		t.Errorf("Automator.LastReconcile() = %v, want %v", a.LastReconcile(), got)
+	}
+
+	again := a.Reconcile(ctx)
+	if len(again.Added)+len(again.Removed)+len(again.Rescheduled) != 0 {
+		t.Errorf("Automator.Reconcile() second run = %+v, want no changes", again)
+	}
+}
+
+func TestAutomator_StartStop(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		blockingJob  bool
+		stopDeadline time.Duration
+		wantStopErr  bool
+	}{
+		{
+			name:         "clean stop",
+			stopDeadline: 5 * time.Second,
+			wantStopErr:  false,
+		},
+		{
+			name:         "running check exceeds deadline",
+			blockingJob:  true,
+			stopDeadline: 50 * time.Millisecond,
+			wantStopErr:  true,
+		},
+		{
+			name:         "deadline passed before stop",
+			blockingJob:  true,
+			stopDeadline: 0,
+			wantStopErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &mock.CacherStore{
+				Cache:    make(map[string]string),
+				CacheSet: make(map[string]struct{}),
+				SetName:  "jobs_set",
+			}
+			scheduler := gocron.NewScheduler(time.Local)
+
+			started := make(chan struct{})
+			release := make(chan struct{})
+			defer close(release)
+			if tt.blockingJob {
+				if _, err := scheduler.Every("1h").Do(func() {
+					close(started)
+					<-release
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), scheduler, zap.NewExample().Sugar())
+
+			if err := a.Start(context.Background()); err != nil {
+				t.Fatalf("Automator.Start() error = %v", err)
+			}
+			if err := a.Start(context.Background()); err == nil {
+				t.Errorf("Automator.Start() on a started automator error = nil, want error")
+			}
+
+			if tt.blockingJob {
+				<-started
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.stopDeadline)
+			defer cancel()
+
+			if err := a.Stop(ctx); (err != nil) != tt.wantStopErr {
+				t.Errorf("Automator.Stop() error = %v, wantErr %v", err, tt.wantStopErr)
+			}
+
+			if a.LastReconcile() == nil {
+				t.Errorf("Automator.LastReconcile() = nil, want initial reconcile result")
+			}
+
+			if len(store.SortedSet) != 0 {
+				t.Errorf("Automator.Stop() left replicas registered: %v", store.SortedSet)
+			}
+
+			if err := a.Start(context.Background()); err == nil {
+				t.Errorf("Automator.Start() on a stopped automator error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestAutomator_StopDrainsRunningChecks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		gracePeriod  time.Duration
+		releaseAfter time.Duration
+		wantErr      bool
+		wantVerdict  automators.Verdict
+	}{
+		{
+			name:         "check finishes within grace period",
+			gracePeriod:  5 * time.Second,
+			releaseAfter: 100 * time.Millisecond,
+			wantErr:      false,
+			wantVerdict:  automators.VerdictUp,
+		},
+		{
+			name:         "check cancelled after grace period",
+			gracePeriod:  100 * time.Millisecond,
+			releaseAfter: 10 * time.Second,
+			wantErr:      true,
+			wantVerdict:  automators.VerdictDown,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			requested := make(chan struct{}, 1)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				select {
+				case requested <- struct{}{}:
+				default:
+				}
+				select {
+				case <-time.After(tt.releaseAfter):
+				case <-r.Context().Done():
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			store := &mock.CacherStore{
+				Cache:    make(map[string]string),
+				CacheSet: make(map[string]struct{}),
+				SetName:  "jobs_set",
+			}
+			scheduler := gocron.NewScheduler(time.Local)
+			a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), scheduler, zap.NewExample().Sugar(),
+				automators.WithShutdownGracePeriod(tt.gracePeriod),
+			)
+
+			ctx := context.Background()
+			if err := a.Start(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			uid, err := a.CreateNewJob(ctx, automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task:           automators.Task{URL: server.URL},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			select {
+			case <-requested:
+			case <-time.After(5 * time.Second):
+				t.Fatal("job was never run")
+			}
+
+			stopCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+			defer cancel()
+
+			if err := a.Stop(stopCtx); (err != nil) != tt.wantErr {
+				t.Errorf("Automator.Stop() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			results, err := a.GetJobResults(ctx, uuid.MustParse(uid), 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("Automator.GetJobResults() returned %d results, want 1", len(results))
+			}
+			if results[0].Verdict != tt.wantVerdict {
+				t.Errorf("Automator.GetJobResults() verdict = %v, want %v", results[0].Verdict, tt.wantVerdict)
+			}
+		})
+	}
+}
+
+func TestAutomator_CapsStoredResults(t *testing.T) {
+	t.Parallel()
+
+	large := `{"data":"` + strings.Repeat("x", 128<<10) + `"}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(large))
+	}))
+	defer server.Close()
+
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar())
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Stop(ctx)
+
+	uid, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "* * * * * *",
+		Task: automators.Task{
+			URL:          server.URL,
+			ResponseBody: &automators.BodyOptions{Snapshot: true, SnapshotBytes: 256 << 10},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		results, err := a.GetJobResults(ctx, uuid.MustParse(uid), 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) > 0 {
+			got := results[0]
+			if !got.Truncated || got.Body != nil || got.Snapshot != nil {
+				t.Errorf("stored result kept its body, truncated = %v", got.Truncated)
+			}
+			if got.Verdict != automators.VerdictUp || got.StatusCode != http.StatusOK {
+				t.Errorf("stored result = %s %d, want up 200", got.Verdict, got.StatusCode)
+			}
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("no result recorded for the job")
+}
+
+func TestAutomator_ResubscribesToJobEvents(t *testing.T) {
+	t.Parallel()
+
+	store := &mock.CacherStore{
+		Cache:             make(map[string]string),
+		CacheSet:          make(map[string]struct{}),
+		SetName:           "jobs_set",
+		SubscribeFailures: 1,
+	}
+	scheduler := gocron.NewScheduler(time.Local)
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), scheduler, zap.NewExample().Sugar())
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Stop(ctx)
+
+	uid, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "0 0 * * * *",
+		Task:           automators.Task{URL: "http://127.0.0.1/ping"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !store.Subscribed("jobs_events") {
+		if time.Now().After(deadline) {
+			t.Fatal("job events were not resubscribed after a failed subscribe")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	event, err := json.Marshal(automators.JobEvent{Type: automators.JobDeleted, UID: uid, ReplicaID: "other-replica"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliverCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	store.Deliver(deliverCtx, "jobs_events", string(event))
+
+	for scheduler.Len() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("job deleted on another replica is still scheduled")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
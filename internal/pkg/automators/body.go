@@ -0,0 +1,155 @@
+package automators
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	maxBodyBytesLimit    = 64 << 20
+	defaultSnapshotBytes = 4 << 10
+)
+
+type responseBody struct {
+	raw       []byte
+	mediaType string
+	encoding  string
+	truncated bool
+}
+
+func validateBodyOptions(options *BodyOptions) error {
+	if options.MaxBytes < 0 || options.MaxBytes > maxBodyBytesLimit {
+		return fmt.Errorf("max body bytes must be between 0 and %d", maxBodyBytesLimit)
+	}
+
+	if options.SnapshotBytes < 0 {
+		return fmt.Errorf("snapshot bytes cannot be negative")
+	}
+
+	if options.SnapshotBytes > 0 && !options.Snapshot {
+		return fmt.Errorf("snapshot bytes requires snapshot to be enabled")
+	}
+	return nil
+}
+
+// readResponseBody undoes the response's content encoding and reads at most
+// limit bytes of the decoded body, so compressed responses cannot expand past
+// the limit.
+func readResponseBody(response *http.Response, limit int64) (*responseBody, error) {
+	body := &responseBody{}
+	if mediaType, _, err := mime.ParseMediaType(response.Header.Get("Content-Type")); err == nil {
+		body.mediaType = mediaType
+	}
+
+	reader := io.Reader(response.Body)
+	if !response.Uncompressed {
+		body.encoding = response.Header.Get("Content-Encoding")
+
+		var err error
+		reader, err = decodeContent(reader, body.encoding)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(raw)) > limit {
+		raw = raw[:limit]
+		body.truncated = true
+	}
+	body.raw = raw
+	return body, nil
+}
+
+func decodeContent(reader io.Reader, contentEncoding string) (io.Reader, error) {
+	if contentEncoding == "" {
+		return reader, nil
+	}
+
+	// encodings are listed in the order they were applied
+	encodings := strings.Split(contentEncoding, ",")
+	for i := len(encodings) - 1; i >= 0; i-- {
+		var err error
+		switch encoding := strings.ToLower(strings.TrimSpace(encodings[i])); encoding {
+		case "", "identity":
+		case "gzip", "x-gzip":
+			reader, err = gzip.NewReader(reader)
+		case "deflate":
+			reader, err = zlib.NewReader(reader)
+		case "br":
+			reader = brotli.NewReader(reader)
+		default:
+			return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s content: %w", encodings[i], err)
+		}
+	}
+	return reader, nil
+}
+
+// decode returns JSON bodies decoded and text bodies as a string. Other media
+// types are not decoded.
+func (b *responseBody) decode() (any, error) {
+	switch {
+	case isJSONMediaType(b.mediaType):
+		var decoded any
+		if err := json.Unmarshal(b.raw, &decoded); err != nil {
+			return nil, err
+		}
+		return decoded, nil
+	case isTextMediaType(b.mediaType):
+		return string(b.raw), nil
+	}
+	return nil, nil
+}
+
+func (b *responseBody) snapshot(size int) *BodySnapshot {
+	if size <= 0 {
+		size = defaultSnapshotBytes
+	}
+
+	data := b.raw
+	truncated := b.truncated
+	if len(data) > size {
+		data = data[:size]
+		truncated = true
+	}
+
+	snapshot := &BodySnapshot{
+		ContentType:     b.mediaType,
+		ContentEncoding: b.encoding,
+		Size:            len(b.raw),
+		Truncated:       truncated,
+	}
+
+	if isJSONMediaType(b.mediaType) || isTextMediaType(b.mediaType) {
+		// the cut may land inside a multi-byte character
+		snapshot.Content = strings.ToValidUTF8(string(data), "")
+	} else {
+		snapshot.Content = base64.StdEncoding.EncodeToString(data)
+		snapshot.Base64 = true
+	}
+	return snapshot
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func isTextMediaType(mediaType string) bool {
+	return strings.HasPrefix(mediaType, "text/")
+}
@@ -0,0 +1,239 @@
+package automators_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func gzipBytes(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func brotliBytes(t *testing.T, data string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	writer := brotli.NewWriter(&buf)
+	if _, err := writer.Write([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestHTTPChecker_ResponseBody(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"status":"ok"}`
+	gzipped := gzipBytes(t, payload)
+	brotlied := brotliBytes(t, payload)
+	large := strings.Repeat("a", 2048)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Write([]byte(payload))
+	})
+	mux.HandleFunc("/text", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte("pong"))
+	})
+	mux.HandleFunc("/gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipped)
+	})
+	mux.HandleFunc("/brotli", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "br")
+		w.Write(brotlied)
+	})
+	mux.HandleFunc("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(large))
+	})
+	mux.HandleFunc("/binary", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write([]byte{0x00, 0xff, 0x10})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	status := map[string]any{"status": "ok"}
+
+	tests := []struct {
+		name         string
+		path         string
+		headers      map[string]string
+		options      *automators.BodyOptions
+		expected     any
+		wantVerdict  automators.Verdict
+		wantBody     any
+		wantSnapshot *automators.BodySnapshot
+	}{
+		{
+			name:        "json with charset",
+			path:        "/json",
+			expected:    status,
+			wantVerdict: automators.VerdictUp,
+			wantBody:    status,
+		},
+		{
+			name:        "text body",
+			path:        "/text",
+			expected:    "pong",
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "gzip encoded",
+			path:        "/gzip",
+			headers:     map[string]string{"Accept-Encoding": "gzip"},
+			expected:    status,
+			wantVerdict: automators.VerdictUp,
+			wantBody:    status,
+		},
+		{
+			name:        "brotli encoded",
+			path:        "/brotli",
+			headers:     map[string]string{"Accept-Encoding": "br"},
+			options:     &automators.BodyOptions{Snapshot: true},
+			expected:    status,
+			wantVerdict: automators.VerdictUp,
+			wantBody:    status,
+			wantSnapshot: &automators.BodySnapshot{
+				ContentType:     "application/json",
+				ContentEncoding: "br",
+				Size:            len(payload),
+				Content:         payload,
+			},
+		},
+		{
+			name:        "body over the size cap",
+			path:        "/large",
+			options:     &automators.BodyOptions{MaxBytes: 1024},
+			expected:    large,
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "truncated snapshot",
+			path:        "/large",
+			options:     &automators.BodyOptions{Snapshot: true, SnapshotBytes: 8},
+			wantVerdict: automators.VerdictUp,
+			wantSnapshot: &automators.BodySnapshot{
+				ContentType: "text/plain",
+				Size:        len(large),
+				Truncated:   true,
+				Content:     "aaaaaaaa",
+			},
+		},
+		{
+			name:        "binary snapshot",
+			path:        "/binary",
+			options:     &automators.BodyOptions{Snapshot: true},
+			wantVerdict: automators.VerdictUp,
+			wantSnapshot: &automators.BodySnapshot{
+				ContentType: "application/octet-stream",
+				Size:        3,
+				Content:     "AP8Q",
+				Base64:      true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			task := automators.Task{
+				URL:              server.URL + tt.path,
+				Headers:          tt.headers,
+				ResponseBody:     tt.options,
+				ExpectedResponse: tt.expected,
+			}
+			if err := automators.NewHTTPChecker().Validate(task); err != nil {
+				t.Fatalf("httpChecker.Validate() error = %v", err)
+			}
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: task})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("httpChecker.Execute() verdict = %s, want %s (error %q)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if !reflect.DeepEqual(got.Body, tt.wantBody) {
+				t.Errorf("httpChecker.Execute() body = %#v, want %#v", got.Body, tt.wantBody)
+			}
+			if !reflect.DeepEqual(got.Snapshot, tt.wantSnapshot) {
+				t.Errorf("httpChecker.Execute() snapshot = %+v, want %+v", got.Snapshot, tt.wantSnapshot)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_ValidateResponseBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		options *automators.BodyOptions
+		wantErr bool
+	}{
+		{
+			name:    "valid options",
+			options: &automators.BodyOptions{MaxBytes: 4096, Snapshot: true, SnapshotBytes: 512},
+		},
+		{
+			name:    "negative max bytes",
+			options: &automators.BodyOptions{MaxBytes: -1},
+			wantErr: true,
+		},
+		{
+			name:    "max bytes over the limit",
+			options: &automators.BodyOptions{MaxBytes: 1 << 30},
+			wantErr: true,
+		},
+		{
+			name:    "snapshot bytes without snapshot",
+			options: &automators.BodyOptions{SnapshotBytes: 512},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := automators.NewHTTPChecker().Validate(automators.Task{URL: "http://127.0.0.1/ping", ResponseBody: tt.options})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("httpChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package automators
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+const (
+	CheckTypeHTTP      = "http"
+	CheckTypeTCP       = "tcp"
+	CheckTypeDNS       = "dns"
+	CheckTypeTLS       = "tls"
+	CheckTypeGRPC      = "grpc"
+	CheckTypeWebSocket = "websocket"
+	CheckTypeHeartbeat = "heartbeat"
+	CheckTypeScenario  = "scenario"
+)
+
+type Checker interface {
+	Validate(task Task) error
+	Execute(ctx context.Context, config *JobConfig) (*RunResult, error)
+}
+
+type CheckerRegistry struct {
+	mu       sync.RWMutex
+	checkers map[string]Checker
+}
+
+type ValidationError struct {
+	Err error
+}
+
+func (v *ValidationError) Error() string {
+	return fmt.Sprintf("invalid job config: %s", v.Err)
+}
+
+func (v *ValidationError) Unwrap() error {
+	return v.Err
+}
+
+func NewCheckerRegistry() *CheckerRegistry {
+	return &CheckerRegistry{
+		checkers: make(map[string]Checker),
+	}
+}
+
+func (r *CheckerRegistry) Register(checkType string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checkers[checkType] = checker
+}
+
+func (r *CheckerRegistry) Get(checkType string) (Checker, error) {
+	if checkType == "" {
+		checkType = CheckTypeHTTP
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	checker, ok := r.checkers[checkType]
+	if !ok {
+		return nil, fmt.Errorf("unknown check type %q", checkType)
+	}
+	return checker, nil
+}
+
+func (r *CheckerRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	types := make([]string, 0, len(r.checkers))
+	for checkType := range r.checkers {
+		types = append(types, checkType)
+	}
+	sort.Strings(types)
+	return types
+}
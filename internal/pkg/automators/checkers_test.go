@@ -0,0 +1,330 @@
+package automators_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/mock"
+)
+
+func TestCheckerRegistry_Get(t *testing.T) {
+	t.Parallel()
+
+	registry := automators.NewCheckerRegistry()
+	registry.Register(automators.CheckTypeHTTP, automators.NewHTTPChecker())
+
+	tests := []struct {
+		name      string
+		checkType string
+		wantErr   bool
+	}{
+		{
+			name:      "registered type",
+			checkType: automators.CheckTypeHTTP,
+			wantErr:   false,
+		},
+		{
+			name:      "empty type defaults to http",
+			checkType: "",
+			wantErr:   false,
+		},
+		{
+			name:      "unknown type",
+			checkType: "carrier-pigeon",
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := registry.Get(tt.checkType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckerRegistry.Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got == nil {
+				t.Errorf("CheckerRegistry.Get() = nil")
+			}
+		})
+	}
+
+	if got := registry.Types(); !reflect.DeepEqual(got, []string{automators.CheckTypeHTTP}) {
+		t.Errorf("CheckerRegistry.Types() = %v", got)
+	}
+}
+
+func TestAutomator_CreateNewJobValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		config automators.JobConfig
+	}{
+		{
+			name: "unknown check type",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Type:           "carrier-pigeon",
+			},
+		},
+		{
+			name: "invalid http url scheme",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task:           automators.Task{URL: "ftp://127.0.0.1/ping"},
+			},
+		},
+		{
+			name: "invalid auth scheme",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:        "http://127.0.0.1/ping",
+					AuthHeader: automators.AuthHeader{Scheme: "Token"},
+				},
+			},
+		},
+		{
+			name: "invalid proxy scheme",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:   "http://127.0.0.1/ping",
+					Proxy: &automators.ProxyConfig{URL: "ftp://127.0.0.1:3128"},
+				},
+			},
+		},
+		{
+			name: "invalid assertion",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:        "http://127.0.0.1/ping",
+					Assertions: []string{`len(body.items > 0`},
+				},
+			},
+		},
+		{
+			name: "invalid redirect mode",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:      "http://127.0.0.1/ping",
+					Redirect: &automators.RedirectPolicy{Mode: "sometimes"},
+				},
+			},
+		},
+		{
+			name: "relative expected final url",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:      "http://127.0.0.1/ping",
+					Redirect: &automators.RedirectPolicy{ExpectedFinalURL: "/home"},
+				},
+			},
+		},
+		{
+			name: "certificate check skipping verification",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:         "https://127.0.0.1/ping",
+					TLS:         &automators.TLSOptions{InsecureSkipVerify: true},
+					Certificate: &automators.CertificateTask{},
+				},
+			},
+		},
+		{
+			name: "disabled proxy with url",
+			config: automators.JobConfig{
+				CronExpression: "* * * * * *",
+				Task: automators.Task{
+					URL:   "http://127.0.0.1/ping",
+					Proxy: &automators.ProxyConfig{URL: "http://127.0.0.1:3128", Disabled: true},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := &mock.CacherStore{
+				Cache:    make(map[string]string),
+				CacheSet: make(map[string]struct{}),
+				SetName:  "jobs_set",
+			}
+			a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar())
+
+			_, err := a.CreateNewJob(context.Background(), tt.config)
+
+			var validationErr *automators.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Automator.CreateNewJob() error = %v, want ValidationError", err)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		statusCode       int
+		contentType      string
+		body             string
+		expectedResponse any
+		wantVerdict      automators.Verdict
+		wantBody         any
+		wantErr          bool
+	}{
+		{
+			name:        "json response",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"status":"ok"}`,
+			wantVerdict: automators.VerdictUp,
+			wantBody:    map[string]any{"status": "ok"},
+		},
+		{
+			name:        "server error",
+			statusCode:  http.StatusInternalServerError,
+			contentType: "text/plain",
+			body:        "oops",
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:             "expected response matches",
+			statusCode:       http.StatusOK,
+			contentType:      "application/json",
+			body:             `{"count":1}`,
+			expectedResponse: map[string]int{"count": 1},
+			wantVerdict:      automators.VerdictUp,
+			wantBody:         map[string]any{"count": float64(1)},
+		},
+		{
+			name:             "expected response mismatch",
+			statusCode:       http.StatusOK,
+			contentType:      "application/json",
+			body:             `{"count":2}`,
+			expectedResponse: map[string]int{"count": 1},
+			wantVerdict:      automators.VerdictDown,
+			wantBody:         map[string]any{"count": float64(2)},
+		},
+		{
+			name:        "invalid json",
+			statusCode:  http.StatusOK,
+			contentType: "application/json",
+			body:        `{"count":`,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(tt.statusCode)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			config := &automators.JobConfig{
+				Task: automators.Task{
+					URL:              server.URL,
+					ExpectedResponse: tt.expectedResponse,
+				},
+			}
+
+			got, err := automators.NewHTTPChecker().Execute(context.Background(), config)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("httpChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("httpChecker.Execute() verdict = %v, want %v", got.Verdict, tt.wantVerdict)
+			}
+			if got.StatusCode != tt.statusCode {
+				t.Errorf("httpChecker.Execute() status code = %v, want %v", got.StatusCode, tt.statusCode)
+			}
+			if !reflect.DeepEqual(got.Body, tt.wantBody) {
+				t.Errorf("httpChecker.Execute() body = %v, want %v", got.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+type panickingChecker struct{}
+
+func (panickingChecker) Validate(task automators.Task) error {
+	return nil
+}
+
+func (panickingChecker) Execute(ctx context.Context, config *automators.JobConfig) (*automators.RunResult, error) {
+	panic("checker bug")
+}
+
+func TestAutomator_RecoversCheckerPanic(t *testing.T) {
+	t.Parallel()
+
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar(),
+		automators.WithChecker("panicking", panickingChecker{}),
+	)
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Stop(ctx)
+
+	jobID, err := a.CreateNewJob(ctx, automators.JobConfig{Type: "panicking", CronExpression: "* * * * * *"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobUID, err := uuid.Parse(jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		results, err := a.GetJobResults(ctx, jobUID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) > 0 {
+			if results[0].Verdict != automators.VerdictDown || results[0].Error != "check panicked: checker bug" {
+				t.Errorf("run result = %+v, want a down verdict for the panic", results[0])
+			}
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("no result recorded for the panicking check")
+}
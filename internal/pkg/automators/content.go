@@ -0,0 +1,156 @@
+package automators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+const (
+	contentDiffContext = 3
+	// only this much of a body is kept to diff the next change against, and
+	// only this much of a diff is published
+	maxContentStateBytes = 64 << 10
+	maxContentDiffBytes  = 16 << 10
+)
+
+type contentState struct {
+	Hash      string `json:"hash"`
+	Body      string `json:"body"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+func validateContentChange(options *ContentChange) error {
+	_, err := compileIgnorePatterns(options.IgnorePatterns)
+	return err
+}
+
+func compileIgnorePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// normalizeContent strips the ignored patterns, such as timestamps or nonces,
+// and line ending and trailing whitespace differences from a body.
+func normalizeContent(raw []byte, ignorePatterns []*regexp.Regexp) string {
+	content := strings.ReplaceAll(string(raw), "\r\n", "\n")
+	for _, re := range ignorePatterns {
+		content = re.ReplaceAllString(content, "")
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncateContent cuts content to at most limit bytes, at the last full line
+// when there is one.
+func truncateContent(content string, limit int) (string, bool) {
+	if len(content) <= limit {
+		return content, false
+	}
+
+	content = content[:limit]
+	if i := strings.LastIndexByte(content, '\n'); i >= 0 {
+		content = content[:i+1]
+	}
+	return content, true
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// detectContentChange compares the run's body with the one stored by the
+// previous run. The first run only stores a baseline.
+func (a *Automator) detectContentChange(ctx context.Context, config *JobConfig, result *RunResult) error {
+	jobID := config.UID.String()
+	current := result.ContentChange
+
+	previous, err := getContentState(ctx, a.cache, jobID)
+	if err != nil {
+		return err
+	}
+
+	if previous != nil && previous.Hash == current.Hash {
+		return nil
+	}
+
+	body, truncated := truncateContent(result.content, maxContentStateBytes)
+
+	if previous != nil {
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(previous.Body),
+			B:        difflib.SplitLines(body),
+			FromFile: previous.Hash,
+			ToFile:   current.Hash,
+			Context:  contentDiffContext,
+		})
+		if err != nil {
+			return fmt.Errorf("error diffing content: %w", err)
+		}
+		diff, diffTruncated := truncateContent(diff, maxContentDiffBytes)
+
+		current.Changed = true
+		current.PreviousHash = previous.Hash
+		a.publishContentChanged(ctx, ContentChangedEvent{
+			UID:          jobID,
+			PreviousHash: previous.Hash,
+			Hash:         current.Hash,
+			Diff:         diff,
+			Truncated:    previous.Truncated || truncated || diffTruncated,
+			DetectedAt:   time.Now(),
+		})
+	}
+
+	return saveContentState(ctx, a.cache, jobID, &contentState{Hash: current.Hash, Body: body, Truncated: truncated})
+}
+
+func getContentState(ctx context.Context, cacher Cacher, jobID string) (*contentState, error) {
+	data, found, err := cacher.LookupData(ctx, contentStateKey(jobID))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving content state: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	var state contentState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("error unmarshalling content state: %w", err)
+	}
+	return &state, nil
+}
+
+func saveContentState(ctx context.Context, cacher Cacher, jobID string, state *contentState) error {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error marshalling content state: %w", err)
+	}
+
+	if err := cacher.InsertData(ctx, contentStateKey(jobID), string(bytes)); err != nil {
+		return fmt.Errorf("error saving content state: %w", err)
+	}
+	return nil
+}
+
+func contentStateKey(jobID string) string {
+	return "content:" + jobID
+}
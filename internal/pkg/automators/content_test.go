@@ -0,0 +1,232 @@
+package automators_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/mock"
+)
+
+func TestHTTPChecker_ContentHash(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<p>rendered at %d</p>\n<h1>welcome</h1>\n", time.Now().UnixNano())
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name     string
+		options  *automators.ContentChange
+		wantSame bool
+	}{
+		{
+			name:     "timestamps ignored",
+			options:  &automators.ContentChange{IgnorePatterns: []string{`rendered at \d+`}},
+			wantSame: true,
+		},
+		{
+			name:     "timestamps compared",
+			options:  &automators.ContentChange{},
+			wantSame: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			task := automators.Task{URL: server.URL, ContentChange: tt.options}
+			if err := automators.NewHTTPChecker().Validate(task); err != nil {
+				t.Fatalf("httpChecker.Validate() error = %v", err)
+			}
+
+			var hashes []string
+			for i := 0; i < 2; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: task})
+				cancel()
+				if err != nil {
+					t.Fatalf("httpChecker.Execute() error = %v", err)
+				}
+				if got.ContentChange == nil {
+					t.Fatal("httpChecker.Execute() did not hash the content")
+				}
+				hashes = append(hashes, got.ContentChange.Hash)
+			}
+
+			if same := hashes[0] == hashes[1]; same != tt.wantSame {
+				t.Errorf("content hashes %v, want same %v", hashes, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_ValidateContentChange(t *testing.T) {
+	t.Parallel()
+
+	err := automators.NewHTTPChecker().Validate(automators.Task{
+		URL:           "http://127.0.0.1/ping",
+		ContentChange: &automators.ContentChange{IgnorePatterns: []string{`(unclosed`}},
+	})
+	if err == nil {
+		t.Error("httpChecker.Validate() accepted an invalid ignore pattern")
+	}
+}
+
+func TestAutomator_ContentChangedEvent(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := "v1"
+		if atomic.AddInt32(&requests, 1) > 1 {
+			version = "v2"
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, "<p>rendered at %d</p>\n<h1>%s</h1>\n", time.Now().UnixNano(), version)
+	}))
+	t.Cleanup(server.Close)
+
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar())
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	jobID, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "* * * * * *",
+		Task: automators.Task{
+			URL:           server.URL,
+			ContentChange: &automators.ContentChange{IgnorePatterns: []string{`rendered at \d+`}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 3 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := a.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var events []automators.ContentChangedEvent
+	for _, message := range store.Published {
+		if !strings.Contains(message, `"diff"`) {
+			continue
+		}
+		var event automators.ContentChangedEvent
+		if err := json.Unmarshal([]byte(message), &event); err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+
+	// only the switch from v1 to v2 is a change, the timestamps are ignored
+	if len(events) != 1 {
+		t.Fatalf("published %d content changed events, want 1", len(events))
+	}
+	event := events[0]
+	if event.UID != jobID {
+		t.Errorf("event uid = %s, want %s", event.UID, jobID)
+	}
+	if !strings.Contains(event.Diff, "-<h1>v1</h1>") || !strings.Contains(event.Diff, "+<h1>v2</h1>") {
+		t.Errorf("event diff does not show the change:\n%s", event.Diff)
+	}
+}
+
+func TestAutomator_ContentChangeCapsLargeBodies(t *testing.T) {
+	t.Parallel()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := "v1"
+		if atomic.AddInt32(&requests, 1) > 1 {
+			version = "v2"
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		for i := 0; i < 10000; i++ {
+			fmt.Fprintf(w, "line %d of %s\n", i, version)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar())
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	jobID, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "* * * * * *",
+		Task: automators.Task{
+			URL:           server.URL,
+			ContentChange: &automators.ContentChange{},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 2 && time.Now().Before(deadline) {
+		time.Sleep(50 * time.Millisecond)
+	}
+	if err := a.Stop(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if state := store.Cache["content:"+jobID]; len(state) > 80<<10 {
+		t.Errorf("stored content state is %d bytes, want the body capped", len(state))
+	}
+
+	var event *automators.ContentChangedEvent
+	for _, message := range store.Published {
+		if strings.Contains(message, `"diff"`) {
+			event = &automators.ContentChangedEvent{}
+			if err := json.Unmarshal([]byte(message), event); err != nil {
+				t.Fatal(err)
+			}
+			break
+		}
+	}
+	if event == nil {
+		t.Fatal("no content changed event was published")
+	}
+	if !event.Truncated {
+		t.Error("event was not marked truncated")
+	}
+	if len(event.Diff) > 16<<10 {
+		t.Errorf("event diff is %d bytes, want it capped", len(event.Diff))
+	}
+	if !strings.Contains(event.Diff, "-line 0 of v1") {
+		t.Errorf("event diff does not start with the change:\n%.200s", event.Diff)
+	}
+}
@@ -0,0 +1,171 @@
+package automators
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+type digestChallenge struct {
+	realm     string
+	nonce     string
+	opaque    string
+	algorithm string
+	qop       []string
+}
+
+// parseDigestChallenges returns the digest challenges from WWW-Authenticate
+// headers, strongest supported algorithm first.
+func parseDigestChallenges(header http.Header) []*digestChallenge {
+	var sha, md []*digestChallenge
+	for _, value := range header.Values("WWW-Authenticate") {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(value), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+
+		fields := parseAuthParams(params)
+		challenge := &digestChallenge{
+			realm:     fields["realm"],
+			nonce:     fields["nonce"],
+			opaque:    fields["opaque"],
+			algorithm: fields["algorithm"],
+		}
+		if challenge.algorithm == "" {
+			challenge.algorithm = "MD5"
+		}
+		for _, qop := range strings.Split(fields["qop"], ",") {
+			if qop = strings.TrimSpace(qop); qop != "" {
+				challenge.qop = append(challenge.qop, qop)
+			}
+		}
+
+		switch strings.ToUpper(challenge.algorithm) {
+		case "SHA-256", "SHA-256-SESS":
+			sha = append(sha, challenge)
+		case "MD5", "MD5-SESS":
+			md = append(md, challenge)
+		}
+	}
+	return append(sha, md...)
+}
+
+func parseAuthParams(params string) map[string]string {
+	fields := make(map[string]string)
+	for params != "" {
+		params = strings.TrimLeft(params, " ,")
+		key, rest, ok := strings.Cut(params, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " ")
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var b strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				b.WriteByte(rest[i])
+			}
+			value = b.String()
+			if i < len(rest) {
+				i++
+			}
+			params = rest[i:]
+		} else {
+			value, params, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+func (c *digestChallenge) authorization(method, uri, username, password string, body []byte) (string, error) {
+	algorithm := strings.ToUpper(c.algorithm)
+
+	var newHash func() hash.Hash
+	switch strings.TrimSuffix(algorithm, "-SESS") {
+	case "MD5":
+		newHash = md5.New
+	case "SHA-256":
+		newHash = sha256.New
+	default:
+		return "", fmt.Errorf("unsupported digest algorithm %q", c.algorithm)
+	}
+
+	h := func(s string) string {
+		sum := newHash()
+		sum.Write([]byte(s))
+		return hex.EncodeToString(sum.Sum(nil))
+	}
+
+	cnonceBytes := make([]byte, 16)
+	if _, err := rand.Read(cnonceBytes); err != nil {
+		return "", fmt.Errorf("error generating cnonce: %w", err)
+	}
+	cnonce := hex.EncodeToString(cnonceBytes)
+	nc := "00000001"
+
+	ha1 := h(fmt.Sprintf("%s:%s:%s", username, c.realm, password))
+	if strings.HasSuffix(algorithm, "-SESS") {
+		ha1 = h(fmt.Sprintf("%s:%s:%s", ha1, c.nonce, cnonce))
+	}
+
+	qop := ""
+	for _, offered := range c.qop {
+		if offered == "auth" {
+			qop = "auth"
+			break
+		}
+		if offered == "auth-int" {
+			qop = "auth-int"
+		}
+	}
+	if len(c.qop) > 0 && qop == "" {
+		return "", fmt.Errorf("unsupported digest qop %q", strings.Join(c.qop, ","))
+	}
+
+	ha2 := h(fmt.Sprintf("%s:%s", method, uri))
+	if qop == "auth-int" {
+		sum := newHash()
+		sum.Write(body)
+		ha2 = h(fmt.Sprintf("%s:%s:%s", method, uri, hex.EncodeToString(sum.Sum(nil))))
+	}
+
+	var response string
+	if qop == "" {
+		response = h(fmt.Sprintf("%s:%s:%s", ha1, c.nonce, ha2))
+	} else {
+		response = h(fmt.Sprintf("%s:%s:%s:%s:%s:%s", ha1, c.nonce, nc, cnonce, qop, ha2))
+	}
+
+	params := []string{
+		"username=" + quoteAuthParam(username),
+		"realm=" + quoteAuthParam(c.realm),
+		"nonce=" + quoteAuthParam(c.nonce),
+		"uri=" + quoteAuthParam(uri),
+		fmt.Sprintf("algorithm=%s", c.algorithm),
+		"response=" + quoteAuthParam(response),
+	}
+	if qop != "" {
+		params = append(params, fmt.Sprintf("qop=%s", qop), fmt.Sprintf("nc=%s", nc), "cnonce="+quoteAuthParam(cnonce))
+	}
+	if c.opaque != "" {
+		params = append(params, "opaque="+quoteAuthParam(c.opaque))
+	}
+	return "Digest " + strings.Join(params, ", "), nil
+}
+
+func quoteAuthParam(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
@@ -0,0 +1,152 @@
+package automators_test
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+var digestParamPattern = regexp.MustCompile(`(\w+)=(?:"([^"]*)"|([^,\s]*))`)
+
+// startDigestServer protects /ping with digest auth, offering the given
+// algorithms in order, and accepts ops:hunter2. /moved redirects to /ping.
+func startDigestServer(t *testing.T, qop string, algorithms ...string) *httptest.Server {
+	t.Helper()
+
+	const realm, nonce, opaque = "ping-app@test", "dcd98b7102dd2f0e8b11d0f600bfb0c093", "5ccc069c403ebaf9f0171e9517f40e41"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/moved" {
+			http.Redirect(w, r, "/ping?verbose=1", http.StatusFound)
+			return
+		}
+
+		header := r.Header.Get("Authorization")
+		if strings.HasPrefix(header, "Digest ") {
+			params := make(map[string]string)
+			for _, match := range digestParamPattern.FindAllStringSubmatch(strings.TrimPrefix(header, "Digest "), -1) {
+				params[match[1]] = match[2] + match[3]
+			}
+
+			var newHash func() hash.Hash
+			switch params["algorithm"] {
+			case "MD5":
+				newHash = md5.New
+			case "SHA-256":
+				newHash = sha256.New
+			}
+			h := func(s string) string {
+				sum := newHash()
+				sum.Write([]byte(s))
+				return hex.EncodeToString(sum.Sum(nil))
+			}
+
+			if newHash != nil && params["opaque"] == opaque && params["uri"] == r.URL.RequestURI() {
+				ha1 := h("ops:" + realm + ":hunter2")
+				ha2 := h(r.Method + ":" + params["uri"])
+				want := h(ha1 + ":" + nonce + ":" + ha2)
+				if qop != "" {
+					want = h(strings.Join([]string{ha1, nonce, params["nc"], params["cnonce"], params["qop"], ha2}, ":"))
+				}
+				if params["username"] == "ops" && params["response"] == want {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+			}
+		}
+
+		for _, algorithm := range algorithms {
+			challenge := fmt.Sprintf(`Digest realm="%s", nonce="%s", opaque="%s", algorithm=%s`, realm, nonce, opaque, algorithm)
+			if qop != "" {
+				challenge += fmt.Sprintf(`, qop="%s"`, qop)
+			}
+			w.Header().Add("WWW-Authenticate", challenge)
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPChecker_Digest(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		server         *httptest.Server
+		path           string
+		password       string
+		wantStatusCode int
+	}{
+		{
+			name:           "md5 with qop",
+			server:         startDigestServer(t, "auth", "MD5"),
+			password:       "hunter2",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "sha-256 preferred over md5",
+			server:         startDigestServer(t, "auth,auth-int", "MD5", "SHA-256"),
+			password:       "hunter2",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "legacy challenge without qop",
+			server:         startDigestServer(t, "", "MD5"),
+			password:       "hunter2",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "challenge after a redirect",
+			server:         startDigestServer(t, "auth", "SHA-256"),
+			path:           "/moved",
+			password:       "hunter2",
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "wrong password",
+			server:         startDigestServer(t, "auth", "SHA-256"),
+			password:       "wrong",
+			wantStatusCode: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			path := tt.path
+			if path == "" {
+				path = "/ping?verbose=1"
+			}
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{
+				Task: automators.Task{
+					URL:        tt.server.URL + path,
+					AuthHeader: automators.AuthHeader{Scheme: "Digest", Username: "ops", Password: tt.password},
+				},
+			})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+
+			if got.StatusCode != tt.wantStatusCode {
+				t.Errorf("httpChecker.Execute() status code = %d, want %d", got.StatusCode, tt.wantStatusCode)
+			}
+		})
+	}
+}
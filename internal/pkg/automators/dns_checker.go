@@ -0,0 +1,187 @@
+package automators
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+var dnsRecordTypes = map[string]struct{}{
+	"A":     {},
+	"AAAA":  {},
+	"CNAME": {},
+	"MX":    {},
+	"TXT":   {},
+	"SRV":   {},
+}
+
+type dnsChecker struct{}
+
+func NewDNSChecker() Checker {
+	return &dnsChecker{}
+}
+
+func (d *dnsChecker) Validate(task Task) error {
+	if task.DNS == nil {
+		return fmt.Errorf("dns check requires dns settings")
+	}
+
+	if task.DNS.Name == "" {
+		return fmt.Errorf("dns check requires a name")
+	}
+
+	if _, ok := dnsRecordTypes[strings.ToUpper(task.DNS.RecordType)]; !ok {
+		return fmt.Errorf("unsupported dns record type %q", task.DNS.RecordType)
+	}
+
+	if task.DNS.MinAnswers < 0 {
+		return fmt.Errorf("min answers must not be negative")
+	}
+	return nil
+}
+
+func (d *dnsChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.DNS
+	if task == nil {
+		return nil, fmt.Errorf("dns check requires dns settings")
+	}
+
+	resolver := newResolver(task.Resolver, config.Task.Proxy)
+
+	start := time.Now()
+	answers, err := lookupRecords(ctx, resolver, strings.ToUpper(task.RecordType), task.Name)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving %s %s: %w", task.RecordType, task.Name, err)
+	}
+
+	result := &RunResult{
+		Verdict: VerdictUp,
+		DNS: &DNSResult{
+			Answers:        answers,
+			ResolutionTime: time.Since(start),
+		},
+	}
+
+	if task.MinAnswers > 0 && len(answers) < task.MinAnswers {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("got %d answers, want at least %d", len(answers), task.MinAnswers)
+		return result, nil
+	}
+
+	missing := make([]string, 0)
+	for _, expected := range task.ExpectedValues {
+		if !containsAnswer(answers, expected) {
+			missing = append(missing, expected)
+		}
+	}
+	if len(missing) > 0 {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("expected values not found in answers: %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+// newResolver queries the given resolver, or the system nameservers when none
+// is set. Behind a proxy both are asked over tcp through the proxy, so the
+// lookup never leaves the replica directly.
+func newResolver(address string, proxy *ProxyConfig) *net.Resolver {
+	proxyURL, _ := parseProxyURL(proxy)
+	if address == "" && proxyURL == nil {
+		return net.DefaultResolver
+	}
+
+	if address != "" {
+		if _, _, err := net.SplitHostPort(address); err != nil {
+			address = net.JoinHostPort(address, "53")
+		}
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, server string) (net.Conn, error) {
+			if address != "" {
+				server = address
+			}
+
+			// proxies only tunnel streams, the resolver falls back to dns over
+			// tcp when handed a stream connection
+			if proxyURL != nil {
+				return dialContext(ctx, proxy, "tcp", server)
+			}
+
+			dialer := net.Dialer{}
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
+
+func lookupRecords(ctx context.Context, resolver *net.Resolver, recordType, name string) ([]string, error) {
+	answers := make([]string, 0)
+
+	switch recordType {
+	case "A", "AAAA":
+		network := "ip4"
+		if recordType == "AAAA" {
+			network = "ip6"
+		}
+		ips, err := resolver.LookupIP(ctx, network, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+
+	case "CNAME":
+		cname, err := resolver.LookupCNAME(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, normalizeDNSName(cname))
+
+	case "MX":
+		records, err := resolver.LookupMX(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, mx := range records {
+			answers = append(answers, fmt.Sprintf("%d %s", mx.Pref, normalizeDNSName(mx.Host)))
+		}
+
+	case "TXT":
+		records, err := resolver.LookupTXT(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		answers = append(answers, records...)
+
+	case "SRV":
+		_, records, err := resolver.LookupSRV(ctx, "", "", name)
+		if err != nil {
+			return nil, err
+		}
+		for _, srv := range records {
+			answers = append(answers, fmt.Sprintf("%d %d %d %s", srv.Priority, srv.Weight, srv.Port, normalizeDNSName(srv.Target)))
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported dns record type %q", recordType)
+	}
+
+	return answers, nil
+}
+
+func containsAnswer(answers []string, expected string) bool {
+	for _, answer := range answers {
+		if answer == expected || normalizeDNSName(answer) == normalizeDNSName(expected) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeDNSName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
@@ -0,0 +1,280 @@
+package automators_test
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+type dnsZone map[string][]dnsmessage.Resource
+
+// startDNSServer serves the zone over UDP on a random local port and returns
+// its address.
+func startDNSServer(t *testing.T, zone dnsZone) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			response, err := answerDNSQuery(zone, buf[:n])
+			if err != nil {
+				continue
+			}
+			conn.WriteTo(response, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func answerDNSQuery(zone dnsZone, packet []byte) ([]byte, error) {
+	var query dnsmessage.Message
+	if err := query.Unpack(packet); err != nil {
+		return nil, err
+	}
+
+	response := dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 query.ID,
+			Response:           true,
+			Authoritative:      true,
+			RecursionAvailable: true,
+		},
+		Questions: query.Questions,
+	}
+
+	if len(query.Questions) == 0 {
+		return response.Pack()
+	}
+	question := query.Questions[0]
+	name := strings.ToLower(question.Name.String())
+
+	records, ok := zone[name]
+	if !ok {
+		response.RCode = dnsmessage.RCodeNameError
+		return response.Pack()
+	}
+
+	for _, record := range records {
+		if record.Header.Type == question.Type {
+			response.Answers = append(response.Answers, record)
+			continue
+		}
+
+		if cname, ok := record.Body.(*dnsmessage.CNAMEResource); ok {
+			response.Answers = append(response.Answers, record)
+			for _, target := range zone[strings.ToLower(cname.CNAME.String())] {
+				if target.Header.Type == question.Type {
+					response.Answers = append(response.Answers, target)
+				}
+			}
+		}
+	}
+
+	return response.Pack()
+}
+
+func dnsRecord(name string, body dnsmessage.ResourceBody) dnsmessage.Resource {
+	var recordType dnsmessage.Type
+	switch body.(type) {
+	case *dnsmessage.AResource:
+		recordType = dnsmessage.TypeA
+	case *dnsmessage.AAAAResource:
+		recordType = dnsmessage.TypeAAAA
+	case *dnsmessage.CNAMEResource:
+		recordType = dnsmessage.TypeCNAME
+	case *dnsmessage.MXResource:
+		recordType = dnsmessage.TypeMX
+	case *dnsmessage.TXTResource:
+		recordType = dnsmessage.TypeTXT
+	case *dnsmessage.SRVResource:
+		recordType = dnsmessage.TypeSRV
+	}
+
+	return dnsmessage.Resource{
+		Header: dnsmessage.ResourceHeader{
+			Name:  dnsmessage.MustNewName(name),
+			Type:  recordType,
+			Class: dnsmessage.ClassINET,
+			TTL:   60,
+		},
+		Body: body,
+	}
+}
+
+func TestDNSChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			task:    automators.Task{DNS: &automators.DNSTask{Name: "example.test", RecordType: "mx"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing settings",
+			task:    automators.Task{},
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			task:    automators.Task{DNS: &automators.DNSTask{RecordType: "A"}},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported record type",
+			task:    automators.Task{DNS: &automators.DNSTask{Name: "example.test", RecordType: "PTR"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewDNSChecker().Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("dnsChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDNSChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	resolver := startDNSServer(t, dnsZone{
+		"api.example.test.": {
+			dnsRecord("api.example.test.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 1}}),
+			dnsRecord("api.example.test.", &dnsmessage.AResource{A: [4]byte{10, 0, 0, 2}}),
+			dnsRecord("api.example.test.", &dnsmessage.AAAAResource{AAAA: [16]byte{15: 1}}),
+		},
+		"www.example.test.": {
+			dnsRecord("www.example.test.", &dnsmessage.CNAMEResource{CNAME: dnsmessage.MustNewName("api.example.test.")}),
+		},
+		"example.test.": {
+			dnsRecord("example.test.", &dnsmessage.MXResource{Pref: 10, MX: dnsmessage.MustNewName("mail.example.test.")}),
+			dnsRecord("example.test.", &dnsmessage.TXTResource{TXT: []string{"v=spf1 -all"}}),
+		},
+		"_sip._tcp.example.test.": {
+			dnsRecord("_sip._tcp.example.test.", &dnsmessage.SRVResource{Priority: 10, Weight: 5, Port: 5060, Target: dnsmessage.MustNewName("sip.example.test.")}),
+		},
+	})
+
+	tests := []struct {
+		name        string
+		task        automators.DNSTask
+		wantAnswers []string
+		wantVerdict automators.Verdict
+		wantErr     bool
+	}{
+		{
+			name:        "A records",
+			task:        automators.DNSTask{Name: "api.example.test", RecordType: "A", ExpectedValues: []string{"10.0.0.2"}, MinAnswers: 2},
+			wantAnswers: []string{"10.0.0.1", "10.0.0.2"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "AAAA record",
+			task:        automators.DNSTask{Name: "api.example.test", RecordType: "AAAA", ExpectedValues: []string{"::1"}},
+			wantAnswers: []string{"::1"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "CNAME record",
+			task:        automators.DNSTask{Name: "www.example.test", RecordType: "CNAME", ExpectedValues: []string{"API.example.test."}},
+			wantAnswers: []string{"api.example.test"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "MX record",
+			task:        automators.DNSTask{Name: "example.test", RecordType: "MX", ExpectedValues: []string{"10 mail.example.test"}},
+			wantAnswers: []string{"10 mail.example.test"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "TXT record",
+			task:        automators.DNSTask{Name: "example.test", RecordType: "TXT", ExpectedValues: []string{"v=spf1 -all"}},
+			wantAnswers: []string{"v=spf1 -all"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "SRV record",
+			task:        automators.DNSTask{Name: "_sip._tcp.example.test", RecordType: "SRV", ExpectedValues: []string{"10 5 5060 sip.example.test"}},
+			wantAnswers: []string{"10 5 5060 sip.example.test"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "unexpected value",
+			task:        automators.DNSTask{Name: "api.example.test", RecordType: "A", ExpectedValues: []string{"10.0.0.3"}},
+			wantAnswers: []string{"10.0.0.1", "10.0.0.2"},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "too few answers",
+			task:        automators.DNSTask{Name: "api.example.test", RecordType: "A", MinAnswers: 3},
+			wantAnswers: []string{"10.0.0.1", "10.0.0.2"},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:    "unknown name",
+			task:    automators.DNSTask{Name: "missing.example.test", RecordType: "A"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			task := tt.task
+			task.Resolver = resolver
+
+			got, err := automators.NewDNSChecker().Execute(ctx, &automators.JobConfig{
+				Type: automators.CheckTypeDNS,
+				Task: automators.Task{DNS: &task},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("dnsChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("dnsChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if !reflect.DeepEqual(got.DNS.Answers, tt.wantAnswers) {
+				t.Errorf("dnsChecker.Execute() answers = %v, want %v", got.DNS.Answers, tt.wantAnswers)
+			}
+			if got.DNS.ResolutionTime <= 0 {
+				t.Errorf("dnsChecker.Execute() resolution time = %v", got.DNS.ResolutionTime)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package automators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+)
+
+const (
+	minEventRetryBackoff = 500 * time.Millisecond
+	maxEventRetryBackoff = 30 * time.Second
+)
+
+type JobEventType string
+
+const (
+	JobCreated JobEventType = "create"
+	JobUpdated JobEventType = "update"
+	JobDeleted JobEventType = "delete"
+)
+
+type JobEvent struct {
+	Type      JobEventType `json:"type"`
+	UID       string       `json:"uid"`
+	ReplicaID string       `json:"replica_id"`
+}
+
+type ContentChangedEvent struct {
+	UID          string `json:"uid"`
+	ReplicaID    string `json:"replica_id"`
+	PreviousHash string `json:"previous_hash"`
+	Hash         string `json:"hash"`
+	Diff         string `json:"diff"`
+	// set when the diff only covers part of the bodies
+	Truncated  bool      `json:"truncated,omitempty"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+func (a *Automator) publishJobEvent(ctx context.Context, eventType JobEventType, jobUID string) {
+	bytes, err := json.Marshal(JobEvent{Type: eventType, UID: jobUID, ReplicaID: a.replicaID})
+	if err != nil {
+		a.logger.Errorf("error marshalling job event: %s", err)
+		return
+	}
+
+	// a lost event is picked up by the next reconcile, so publishing is best effort
+	if err := a.cache.Publish(ctx, a.eventChannel, string(bytes)); err != nil {
+		a.logger.Errorw("error publishing job event", "type", eventType, "job_id", jobUID, "error", err)
+	}
+}
+
+func (a *Automator) publishContentChanged(ctx context.Context, event ContentChangedEvent) {
+	event.ReplicaID = a.replicaID
+	bytes, err := json.Marshal(event)
+	if err != nil {
+		a.logger.Errorf("error marshalling content changed event: %s", err)
+		return
+	}
+
+	if err := a.cache.Publish(ctx, a.contentChannel, string(bytes)); err != nil {
+		a.logger.Errorw("error publishing content changed event", "job_id", event.UID, "error", err)
+	}
+}
+
+// watchJobEvents subscribes to job events until ctx is done, resubscribing
+// with backoff when the subscription fails or is closed.
+func (a *Automator) watchJobEvents(ctx context.Context) {
+	backoff := minEventRetryBackoff
+	for {
+		messages, err := a.cache.Subscribe(ctx, a.eventChannel)
+		if err != nil {
+			a.logger.Errorw("error subscribing to job events", "error", err, "retry_in", backoff.String())
+		} else {
+			backoff = minEventRetryBackoff
+			a.handleJobEvents(ctx, messages)
+			if ctx.Err() == nil {
+				a.logger.Warnw("job event subscription closed", "retry_in", backoff.String())
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+
+		if err != nil {
+			backoff *= 2
+			if backoff > maxEventRetryBackoff {
+				backoff = maxEventRetryBackoff
+			}
+		}
+	}
+}
+
+func (a *Automator) handleJobEvents(ctx context.Context, messages <-chan string) {
+	for message := range messages {
+		var event JobEvent
+		if err := json.Unmarshal([]byte(message), &event); err != nil {
+			a.logger.Errorf("error unmarshalling job event: %s", err)
+			continue
+		}
+
+		if event.ReplicaID == a.replicaID {
+			continue
+		}
+
+		if err := a.applyJobEvent(ctx, event); err != nil {
+			a.logger.Errorw("error applying job event", "type", event.Type, "job_id", event.UID, "error", err)
+		}
+	}
+}
+
+func (a *Automator) applyJobEvent(ctx context.Context, event JobEvent) error {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+
+	switch event.Type {
+	case JobDeleted:
+		return a.unscheduleJob(event.UID)
+
+	case JobCreated, JobUpdated:
+		if !a.ownsJob(event.UID) {
+			return a.unscheduleJob(event.UID)
+		}
+
+		data, err := a.cache.GetData(ctx, event.UID)
+		if err != nil {
+			return fmt.Errorf("error retrieving job data: %w", err)
+		}
+
+		config, err := DecryptJobInfo(a.secretKey, data)
+		if err != nil {
+			return fmt.Errorf("error decrypting job data: %w", err)
+		}
+
+		if err := a.unscheduleJob(event.UID); err != nil {
+			return err
+		}
+		return a.scheduleJob(config)
+
+	default:
+		return fmt.Errorf("unknown job event type %q", event.Type)
+	}
+}
+
+func (a *Automator) unscheduleJob(jobUID string) error {
+	if err := a.scheduler.RemoveByTag(jobUID); err != nil && err != gocron.ErrJobNotFoundWithTag {
+		return fmt.Errorf("error removing job from scheduler: %w", err)
+	}
+	delete(a.scheduledHashes, jobUID)
+	a.transports.Release(jobUID)
+	a.metrics.forget(jobUID)
+	return nil
+}
@@ -0,0 +1,97 @@
+package automators
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type grpcChecker struct{}
+
+func NewGRPCChecker() Checker {
+	return &grpcChecker{}
+}
+
+func (g *grpcChecker) Validate(task Task) error {
+	if task.GRPC == nil {
+		return fmt.Errorf("grpc check requires grpc settings")
+	}
+
+	if _, _, err := net.SplitHostPort(task.GRPC.Address); err != nil {
+		return fmt.Errorf("invalid grpc address: %w", err)
+	}
+
+	if !task.GRPC.TLS && (task.GRPC.ServerName != "" || task.GRPC.InsecureSkipVerify) {
+		return fmt.Errorf("server name and insecure skip verify require tls")
+	}
+	return nil
+}
+
+func (g *grpcChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.GRPC
+	if task == nil {
+		return nil, fmt.Errorf("grpc check requires grpc settings")
+	}
+
+	creds := insecure.NewCredentials()
+	if task.TLS {
+		creds = credentials.NewTLS(&tls.Config{
+			ServerName:         task.ServerName,
+			InsecureSkipVerify: task.InsecureSkipVerify,
+		})
+	}
+
+	dialer := func(ctx context.Context, address string) (net.Conn, error) {
+		return dialContext(ctx, config.Task.Proxy, "tcp", address)
+	}
+
+	// the dial does not block, a blocking dial retries a failed handshake until
+	// the deadline and hides why it failed. The health check fails fast with
+	// the connection error instead.
+	conn, err := grpc.DialContext(ctx, task.Address, grpc.WithTransportCredentials(creds), grpc.WithContextDialer(dialer))
+	if err != nil {
+		return nil, fmt.Errorf("error connecting: %w", err)
+	}
+	defer conn.Close()
+
+	if len(task.Metadata) > 0 {
+		ctx = metadata.NewOutgoingContext(ctx, metadata.New(task.Metadata))
+	}
+
+	response, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: task.Service})
+	if err != nil {
+		if s, ok := status.FromError(err); ok && s.Code() == codes.NotFound {
+			return &RunResult{
+				Verdict: VerdictDown,
+				Error:   fmt.Sprintf("service %q unknown to health server", task.Service),
+				GRPC:    &GRPCResult{Status: healthpb.HealthCheckResponse_SERVICE_UNKNOWN.String()},
+			}, nil
+		}
+		return nil, fmt.Errorf("error checking health: %w", err)
+	}
+
+	result := &RunResult{
+		GRPC: &GRPCResult{Status: response.Status.String()},
+	}
+
+	switch response.Status {
+	case healthpb.HealthCheckResponse_SERVING:
+		result.Verdict = VerdictUp
+	case healthpb.HealthCheckResponse_UNKNOWN:
+		result.Verdict = VerdictWarn
+		result.Error = "health server reported unknown status"
+	default:
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("health server reported %s", response.Status)
+	}
+	return result, nil
+}
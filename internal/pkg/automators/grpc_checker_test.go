@@ -0,0 +1,199 @@
+package automators_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func startGRPCHealthServer(t *testing.T, opts ...grpc.ServerOption) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("payments", healthpb.HealthCheckResponse_SERVING)
+	healthServer.SetServingStatus("ledger", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus("reports", healthpb.HealthCheckResponse_UNKNOWN)
+
+	opts = append(opts, grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("x-required"); len(values) > 0 && values[0] != "secret" {
+			return nil, status.Error(codes.PermissionDenied, "bad metadata")
+		}
+		return handler(ctx, req)
+	}))
+
+	server := grpc.NewServer(opts...)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func TestGRPCChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	cert := ca.issue(t, time.Now().Add(90*24*time.Hour), x509.ExtKeyUsageServerAuth)
+
+	plaintextAddr := startGRPCHealthServer(t)
+	tlsAddr := startGRPCHealthServer(t, grpc.Creds(credentials.NewTLS(&tls.Config{Certificates: []tls.Certificate{cert}})))
+
+	tests := []struct {
+		name        string
+		task        automators.GRPCTask
+		wantVerdict automators.Verdict
+		wantStatus  string
+		wantErr     bool
+		wantErrText string
+	}{
+		{
+			name:        "serving",
+			task:        automators.GRPCTask{Address: plaintextAddr, Service: "payments"},
+			wantVerdict: automators.VerdictUp,
+			wantStatus:  "SERVING",
+		},
+		{
+			name:        "overall server health",
+			task:        automators.GRPCTask{Address: plaintextAddr},
+			wantVerdict: automators.VerdictUp,
+			wantStatus:  "SERVING",
+		},
+		{
+			name:        "not serving",
+			task:        automators.GRPCTask{Address: plaintextAddr, Service: "ledger"},
+			wantVerdict: automators.VerdictDown,
+			wantStatus:  "NOT_SERVING",
+		},
+		{
+			name:        "unknown status",
+			task:        automators.GRPCTask{Address: plaintextAddr, Service: "reports"},
+			wantVerdict: automators.VerdictWarn,
+			wantStatus:  "UNKNOWN",
+		},
+		{
+			name:        "unregistered service",
+			task:        automators.GRPCTask{Address: plaintextAddr, Service: "missing"},
+			wantVerdict: automators.VerdictDown,
+			wantStatus:  "SERVICE_UNKNOWN",
+		},
+		{
+			name:        "metadata headers",
+			task:        automators.GRPCTask{Address: plaintextAddr, Service: "payments", Metadata: map[string]string{"x-required": "secret"}},
+			wantVerdict: automators.VerdictUp,
+			wantStatus:  "SERVING",
+		},
+		{
+			name:    "rejected metadata",
+			task:    automators.GRPCTask{Address: plaintextAddr, Service: "payments", Metadata: map[string]string{"x-required": "wrong"}},
+			wantErr: true,
+		},
+		{
+			name:        "tls",
+			task:        automators.GRPCTask{Address: tlsAddr, Service: "payments", TLS: true, ServerName: "service.test", InsecureSkipVerify: true},
+			wantVerdict: automators.VerdictUp,
+			wantStatus:  "SERVING",
+		},
+		{
+			name:        "untrusted tls",
+			task:        automators.GRPCTask{Address: tlsAddr, Service: "payments", TLS: true, ServerName: "service.test"},
+			wantErr:     true,
+			wantErrText: "certificate",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			task := tt.task
+			got, err := automators.NewGRPCChecker().Execute(ctx, &automators.JobConfig{
+				Type: automators.CheckTypeGRPC,
+				Task: automators.Task{GRPC: &task},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("grpcChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if ctx.Err() != nil {
+					t.Errorf("grpcChecker.Execute() ran until the deadline instead of failing fast")
+				}
+				if !strings.Contains(err.Error(), tt.wantErrText) {
+					t.Errorf("grpcChecker.Execute() error = %v, want it to mention %q", err, tt.wantErrText)
+				}
+				return
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("grpcChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if got.GRPC == nil || got.GRPC.Status != tt.wantStatus {
+				t.Errorf("grpcChecker.Execute() status = %+v, want %v", got.GRPC, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGRPCChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			task:    automators.Task{GRPC: &automators.GRPCTask{Address: "payments.internal:50051"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing settings",
+			task:    automators.Task{},
+			wantErr: true,
+		},
+		{
+			name:    "missing port",
+			task:    automators.Task{GRPC: &automators.GRPCTask{Address: "payments.internal"}},
+			wantErr: true,
+		},
+		{
+			name:    "tls options without tls",
+			task:    automators.Task{GRPC: &automators.GRPCTask{Address: "payments.internal:50051", InsecureSkipVerify: true}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewGRPCChecker().Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("grpcChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
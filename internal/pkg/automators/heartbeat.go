@@ -0,0 +1,222 @@
+package automators
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/cache"
+)
+
+type heartbeatState struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type heartbeatChecker struct {
+	cache Cacher
+}
+
+func NewHeartbeatChecker(cache Cacher) Checker {
+	return &heartbeatChecker{cache: cache}
+}
+
+func (h *heartbeatChecker) Validate(task Task) error {
+	if task.Heartbeat == nil {
+		return fmt.Errorf("heartbeat check requires heartbeat settings")
+	}
+
+	if task.Heartbeat.Schedule != "" {
+		if _, err := parseCronExpression(task.Heartbeat.Schedule); err != nil {
+			return fmt.Errorf("invalid heartbeat schedule: %w", err)
+		}
+	}
+
+	if task.Heartbeat.GracePeriod < 0 {
+		return fmt.Errorf("heartbeat grace period cannot be negative")
+	}
+	return nil
+}
+
+func (h *heartbeatChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.Heartbeat
+	if task == nil {
+		return nil, fmt.Errorf("heartbeat check requires heartbeat settings")
+	}
+
+	expression := task.Schedule
+	if expression == "" {
+		expression = config.CronExpression
+	}
+
+	schedule, err := parseCronExpression(expression)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing heartbeat schedule: %w", err)
+	}
+
+	state, err := getHeartbeatState(ctx, h.cache, config.UID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	lastPing, err := getLastHeartbeat(ctx, h.cache, config.UID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	since := state.CreatedAt
+	if lastPing != nil {
+		since = *lastPing
+	}
+
+	result := &RunResult{
+		Verdict: VerdictUp,
+		Heartbeat: &HeartbeatResult{
+			ExpectedBy: schedule.Next(since).Add(task.GracePeriod),
+			LastPing:   lastPing,
+		},
+	}
+
+	if time.Now().After(result.Heartbeat.ExpectedBy) {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("no heartbeat received since %s", since.Format(time.RFC3339))
+	}
+	return result, nil
+}
+
+func (a *Automator) RecordHeartbeat(ctx context.Context, token string) error {
+	logger := a.logger.With("context", ctx)
+
+	jobID, err := a.cache.GetData(ctx, heartbeatTokenKey(token))
+	if err != nil {
+		if _, ok := err.(*cache.NotFoundError); ok {
+			return err
+		}
+		err := fmt.Errorf("error retrieving heartbeat token: %w", err)
+		logger.Error(err)
+		return err
+	}
+
+	// a single write, so concurrent pings cannot overwrite each other's
+	// read-modify-write of the state
+	if err := a.cache.InsertData(ctx, heartbeatPingKey(jobID), time.Now().Format(time.RFC3339Nano)); err != nil {
+		err := fmt.Errorf("error saving heartbeat: %w", err)
+		logger.Error(err)
+		return err
+	}
+
+	logger.Debugw("recorded heartbeat", "jobUID", jobID)
+	return nil
+}
+
+func (a *Automator) registerHeartbeat(ctx context.Context, config *JobConfig) error {
+	jobID := config.UID.String()
+	if err := a.cache.InsertData(ctx, heartbeatTokenKey(config.Task.Heartbeat.Token), jobID); err != nil {
+		return fmt.Errorf("error storing heartbeat token: %w", err)
+	}
+
+	_, found, err := a.cache.LookupData(ctx, heartbeatStateKey(jobID))
+	if err != nil {
+		return fmt.Errorf("error retrieving heartbeat state: %w", err)
+	}
+	if found {
+		return nil
+	}
+	return saveHeartbeatState(ctx, a.cache, jobID, &heartbeatState{CreatedAt: time.Now()})
+}
+
+func (a *Automator) unregisterHeartbeat(ctx context.Context, jobID, token string) error {
+	if token != "" {
+		if err := a.cache.DeleteData(ctx, heartbeatTokenKey(token)); err != nil {
+			return fmt.Errorf("error removing heartbeat token: %w", err)
+		}
+	}
+
+	if err := a.cache.DeleteData(ctx, heartbeatStateKey(jobID)); err != nil {
+		return fmt.Errorf("error removing heartbeat state: %w", err)
+	}
+
+	if err := a.cache.DeleteData(ctx, heartbeatPingKey(jobID)); err != nil {
+		return fmt.Errorf("error removing heartbeat: %w", err)
+	}
+	return nil
+}
+
+func heartbeatToken(config *JobConfig) string {
+	if config == nil || config.Task.Heartbeat == nil {
+		return ""
+	}
+	return config.Task.Heartbeat.Token
+}
+
+// getHeartbeatState fails for a job that was never registered, so a run
+// cannot mistake a lost state for a freshly created monitor.
+func getHeartbeatState(ctx context.Context, cacher Cacher, jobID string) (*heartbeatState, error) {
+	data, found, err := cacher.LookupData(ctx, heartbeatStateKey(jobID))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving heartbeat state: %w", err)
+	}
+	if !found {
+		return nil, fmt.Errorf("heartbeat state not found")
+	}
+
+	var state heartbeatState
+	if err := json.Unmarshal([]byte(data), &state); err != nil {
+		return nil, fmt.Errorf("error unmarshalling heartbeat state: %w", err)
+	}
+	return &state, nil
+}
+
+// getLastHeartbeat returns nil until the job's first ping.
+func getLastHeartbeat(ctx context.Context, cacher Cacher, jobID string) (*time.Time, error) {
+	data, found, err := cacher.LookupData(ctx, heartbeatPingKey(jobID))
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving heartbeat: %w", err)
+	}
+	if !found {
+		return nil, nil
+	}
+
+	ping, err := time.Parse(time.RFC3339Nano, data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing heartbeat: %w", err)
+	}
+	return &ping, nil
+}
+
+func saveHeartbeatState(ctx context.Context, cacher Cacher, jobID string, state *heartbeatState) error {
+	bytes, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("error marshalling heartbeat state: %w", err)
+	}
+
+	if err := cacher.InsertData(ctx, heartbeatStateKey(jobID), string(bytes)); err != nil {
+		return fmt.Errorf("error saving heartbeat state: %w", err)
+	}
+	return nil
+}
+
+func newHeartbeatToken() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating heartbeat token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// tokens are stored hashed so the cache never holds a usable ingest URL
+func heartbeatTokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "heartbeat_token:" + hex.EncodeToString(sum[:])
+}
+
+func heartbeatStateKey(jobID string) string {
+	return "heartbeat:" + jobID
+}
+
+func heartbeatPingKey(jobID string) string {
+	return "heartbeat_ping:" + jobID
+}
@@ -0,0 +1,238 @@
+package automators_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/cache"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/mock"
+)
+
+func TestHeartbeatChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	tests := []struct {
+		name        string
+		state       map[string]time.Time
+		task        automators.HeartbeatTask
+		wantVerdict automators.Verdict
+		wantErr     bool
+	}{
+		{
+			name:        "recent ping",
+			state:       map[string]time.Time{"created_at": now.Add(-time.Hour), "last_ping": now.Add(-10 * time.Second)},
+			task:        automators.HeartbeatTask{Schedule: "0 * * * * *", GracePeriod: 30 * time.Second},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "missed ping",
+			state:       map[string]time.Time{"created_at": now.Add(-time.Hour), "last_ping": now.Add(-5 * time.Minute)},
+			task:        automators.HeartbeatTask{Schedule: "0 * * * * *", GracePeriod: 30 * time.Second},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "missed ping within grace period",
+			state:       map[string]time.Time{"created_at": now.Add(-time.Hour), "last_ping": now.Add(-5 * time.Minute)},
+			task:        automators.HeartbeatTask{Schedule: "0 * * * * *", GracePeriod: 10 * time.Minute},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "never pinged after schedule elapsed",
+			state:       map[string]time.Time{"created_at": now.Add(-5 * time.Minute)},
+			task:        automators.HeartbeatTask{Schedule: "0 * * * * *"},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "never pinged before first expected ping",
+			state:       map[string]time.Time{"created_at": now},
+			task:        automators.HeartbeatTask{Schedule: "@hourly"},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "defaults to job schedule",
+			state:       map[string]time.Time{"created_at": now.Add(-time.Hour), "last_ping": now.Add(-5 * time.Minute)},
+			task:        automators.HeartbeatTask{},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:    "missing state",
+			state:   map[string]time.Time{"last_ping": now.Add(-10 * time.Second)},
+			task:    automators.HeartbeatTask{Schedule: "0 * * * * *"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			uid := uuid.New()
+			cacher := &mock.CacherStore{Cache: make(map[string]string)}
+			if createdAt, ok := tt.state["created_at"]; ok {
+				state, err := json.Marshal(map[string]time.Time{"created_at": createdAt})
+				if err != nil {
+					t.Fatal(err)
+				}
+				cacher.Cache["heartbeat:"+uid.String()] = string(state)
+			}
+			if lastPing, ok := tt.state["last_ping"]; ok {
+				cacher.Cache["heartbeat_ping:"+uid.String()] = lastPing.Format(time.RFC3339Nano)
+			}
+
+			task := tt.task
+			got, err := automators.NewHeartbeatChecker(cacher).Execute(context.Background(), &automators.JobConfig{
+				CronExpression: "0 * * * * *",
+				UID:            uid,
+				Type:           automators.CheckTypeHeartbeat,
+				Task:           automators.Task{Heartbeat: &task},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("heartbeatChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("heartbeatChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if _, pinged := tt.state["last_ping"]; pinged != (got.Heartbeat.LastPing != nil) {
+				t.Errorf("heartbeatChecker.Execute() last ping = %v, want pinged %v", got.Heartbeat.LastPing, pinged)
+			}
+		})
+	}
+}
+
+func TestHeartbeatChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			task:    automators.Task{Heartbeat: &automators.HeartbeatTask{Schedule: "0 */5 * * * *", GracePeriod: time.Minute}},
+			wantErr: false,
+		},
+		{
+			name:    "job schedule",
+			task:    automators.Task{Heartbeat: &automators.HeartbeatTask{}},
+			wantErr: false,
+		},
+		{
+			name:    "missing settings",
+			task:    automators.Task{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid schedule",
+			task:    automators.Task{Heartbeat: &automators.HeartbeatTask{Schedule: "* * * rv *"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative grace period",
+			task:    automators.Task{Heartbeat: &automators.HeartbeatTask{GracePeriod: -time.Minute}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewHeartbeatChecker(&mock.CacherStore{}).Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("heartbeatChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAutomator_RecordHeartbeat(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cacher := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	scheduler := gocron.NewScheduler(time.Local)
+	a := automators.NewAutomator(cacher, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), scheduler, zap.NewExample().Sugar())
+
+	uid, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "0 * * * * *",
+		Type:           automators.CheckTypeHeartbeat,
+		Task:           automators.Task{Heartbeat: &automators.HeartbeatTask{GracePeriod: time.Minute}},
+	})
+	if err != nil {
+		t.Fatalf("Automator.CreateNewJob() error = %v", err)
+	}
+
+	config, err := a.GetJob(ctx, uuid.MustParse(uid))
+	if err != nil {
+		t.Fatalf("Automator.GetJob() error = %v", err)
+	}
+	token := config.Task.Heartbeat.Token
+	if token == "" {
+		t.Fatal("Automator.CreateNewJob() did not generate a heartbeat token")
+	}
+
+	if err := a.RecordHeartbeat(ctx, "unknown"); err == nil {
+		t.Error("Automator.RecordHeartbeat() expected error for unknown token")
+	} else if _, ok := err.(*cache.NotFoundError); !ok {
+		t.Errorf("Automator.RecordHeartbeat() error = %v, want not found", err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- a.RecordHeartbeat(ctx, token)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Automator.RecordHeartbeat() error = %v", err)
+		}
+	}
+
+	got, err := automators.NewHeartbeatChecker(cacher).Execute(ctx, config)
+	if err != nil {
+		t.Fatalf("heartbeatChecker.Execute() error = %v", err)
+	}
+	if got.Verdict != automators.VerdictUp || got.Heartbeat.LastPing == nil {
+		t.Errorf("heartbeatChecker.Execute() = %+v, want up with last ping", got.Heartbeat)
+	}
+
+	config.Task.Heartbeat.Token = ""
+	if err := a.UpdateJob(ctx, config.UID, *config); err != nil {
+		t.Fatalf("Automator.UpdateJob() error = %v", err)
+	}
+	if err := a.RecordHeartbeat(ctx, token); err != nil {
+		t.Errorf("Automator.RecordHeartbeat() token not preserved across update: %v", err)
+	}
+
+	if err := a.DeleteJob(ctx, config.UID); err != nil {
+		t.Fatalf("Automator.DeleteJob() error = %v", err)
+	}
+	if err := a.RecordHeartbeat(ctx, token); err == nil {
+		t.Error("Automator.RecordHeartbeat() token still valid after delete")
+	}
+	scheduler.Stop()
+}
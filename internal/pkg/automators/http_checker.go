@@ -0,0 +1,401 @@
+package automators
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	maxResponseBodyBytes = 1 << 20
+	maxDrainBytes        = 64 << 10
+)
+
+type httpChecker struct {
+	tokens     *oauth2TokenCache
+	transports *TransportPool
+}
+
+func NewHTTPChecker() Checker {
+	return NewHTTPCheckerWithTransports(NewTransportPool(TransportSettings{}))
+}
+
+func NewHTTPCheckerWithTransports(transports *TransportPool) Checker {
+	return &httpChecker{
+		tokens:     newOAuth2TokenCache(),
+		transports: transports,
+	}
+}
+
+func (h *httpChecker) Validate(task Task) error {
+	u, err := url.Parse(task.URL)
+	if err != nil {
+		return fmt.Errorf("invalid url: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("url scheme must be http or https")
+	}
+
+	scheme := task.AuthHeader.Scheme
+	if scheme != "" && scheme != "Bearer" && scheme != "Basic" && scheme != "Digest" {
+		return fmt.Errorf("invalid scheme")
+	}
+
+	if task.AuthHeader.Username != "" && scheme != "Digest" {
+		return fmt.Errorf("username and password are only supported with digest")
+	}
+
+	if task.OAuth2 != nil {
+		if scheme != "" {
+			return fmt.Errorf("oauth2 cannot be combined with an auth header")
+		}
+		if err := validateOAuth2(task.OAuth2); err != nil {
+			return err
+		}
+	}
+
+	if task.Signing != nil {
+		if err := validateSigning(task.Signing); err != nil {
+			return err
+		}
+	}
+
+	if task.Redirect != nil {
+		if err := validateRedirectPolicy(task.Redirect); err != nil {
+			return err
+		}
+	}
+
+	if task.ResponseBody != nil {
+		if err := validateBodyOptions(task.ResponseBody); err != nil {
+			return err
+		}
+	}
+
+	if err := validateAssertions(task.Assertions); err != nil {
+		return err
+	}
+
+	if task.Script != nil {
+		if err := validateScript(task.Script); err != nil {
+			return err
+		}
+	}
+
+	if task.ContentChange != nil {
+		if err := validateContentChange(task.ContentChange); err != nil {
+			return err
+		}
+	}
+
+	if task.TLS != nil {
+		if u.Scheme != "https" {
+			return fmt.Errorf("tls options require an https url")
+		}
+		if err := validateTLSOptions(task.TLS); err != nil {
+			return err
+		}
+	}
+
+	if task.Certificate != nil {
+		if u.Scheme != "https" {
+			return fmt.Errorf("certificate checks require an https url")
+		}
+		// the check relies on the transport verifying the chain and hostname
+		if task.TLS != nil && task.TLS.InsecureSkipVerify {
+			return fmt.Errorf("certificate checks cannot skip tls verification")
+		}
+		return validateCertificateTask(task.Certificate)
+	}
+	return nil
+}
+
+func (h *httpChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	client, release, err := h.transports.Client(config.UID.String(), &config.Task)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	method := config.Task.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	var body io.Reader
+	if config.Task.Body != "" {
+		body = strings.NewReader(config.Task.Body)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, config.Task.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	for key, value := range config.Task.Headers {
+		request.Header.Set(key, value)
+	}
+
+	scheme := config.Task.AuthHeader.Scheme
+	auth := ""
+	if scheme != "" {
+		if scheme != "Bearer" && scheme != "Basic" && scheme != "Digest" {
+			return nil, fmt.Errorf("invalid scheme")
+		}
+		auth = fmt.Sprintf("%s %s", scheme, config.Task.AuthHeader.Parameters)
+	}
+
+	// digest credentials are answered to the server's challenge below
+	digest := scheme == "Digest" && config.Task.AuthHeader.Username != ""
+	if digest {
+		auth = ""
+	}
+
+	if config.Task.OAuth2 != nil {
+		auth, err = h.tokens.authorization(ctx, client, config.Task.OAuth2)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching oauth2 token: %w", err)
+		}
+	}
+
+	request.Header.Add("Authorization", auth)
+
+	if config.Task.Signing != nil {
+		if err := signRequest(request, []byte(config.Task.Body), config.Task.Signing, time.Now()); err != nil {
+			return nil, fmt.Errorf("error signing request: %w", err)
+		}
+	}
+
+	timer := newHTTPTimer()
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), timer.trace()))
+
+	redirects := newRedirectRecorder(config.Task.Redirect)
+	client.CheckRedirect = redirects.checkRedirect
+
+	sent := time.Now()
+	response, err := client.Do(request)
+	if errors.Is(err, errTooManyRedirects) && response != nil {
+		return &RunResult{
+			StatusCode: response.StatusCode,
+			Verdict:    VerdictDown,
+			Error:      fmt.Sprintf("stopped after %d redirects", redirects.maxHops()),
+			Redirects:  redirects.result(response),
+		}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+
+	if digest && response.StatusCode == http.StatusUnauthorized {
+		response, err = h.retryWithDigest(client, request, response, config.Task)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer func() {
+		// drain what is left so the connection can go back to the pool
+		io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainBytes))
+		response.Body.Close()
+	}()
+
+	// a rejected token may have been revoked early, fetch a new one next run
+	if response.StatusCode == http.StatusUnauthorized && config.Task.OAuth2 != nil {
+		h.tokens.invalidate(config.Task.OAuth2)
+	}
+
+	result := &RunResult{
+		StatusCode: response.StatusCode,
+		Verdict:    VerdictUp,
+		Redirects:  redirects.result(response),
+	}
+
+	limit := int64(maxResponseBodyBytes)
+	options := config.Task.ResponseBody
+	if options != nil && options.MaxBytes > 0 {
+		limit = options.MaxBytes
+	}
+
+	received, err := readResponseBody(response, limit)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	latency := time.Since(sent)
+	result.Timings = timer.timings(time.Now())
+
+	if options != nil && options.Snapshot {
+		result.Snapshot = received.snapshot(options.SnapshotBytes)
+	}
+
+	// a truncated body cannot be decoded
+	var r any
+	if !received.truncated {
+		r, err = received.decode()
+		if err != nil {
+			return nil, fmt.Errorf("error decoding response: %w", err)
+		}
+	}
+	// text bodies are only kept in the snapshot, results are logged and
+	// stored in the job's history
+	if isJSONMediaType(received.mediaType) {
+		result.Body = r
+	}
+
+	if config.Task.ContentChange != nil {
+		// scheduled jobs carry their ignore patterns compiled
+		patterns := config.ignorePatterns
+		if patterns == nil {
+			if patterns, err = compileIgnorePatterns(config.Task.ContentChange.IgnorePatterns); err != nil {
+				return nil, err
+			}
+		}
+		content := normalizeContent(received.raw, patterns)
+		result.content = content
+		result.ContentChange = &ContentChangeResult{Hash: contentHash(content)}
+	}
+
+	if config.Task.Certificate != nil {
+		if response.TLS == nil {
+			result.Verdict = VerdictDown
+			result.Error = "response was not served over tls"
+			return result, nil
+		}
+
+		// the transport has already verified the chain and hostname
+		evaluateCertificates(result, response.TLS.PeerCertificates, nil, config.Task.Certificate, time.Now())
+		if result.Verdict == VerdictDown {
+			return result, nil
+		}
+	}
+
+	evaluateRedirects(result, config.Task.Redirect)
+	if result.Verdict == VerdictDown {
+		return result, nil
+	}
+
+	if response.StatusCode >= http.StatusBadRequest {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("unexpected status code %d", response.StatusCode)
+		return result, nil
+	}
+
+	if config.Task.ExpectedResponse != nil {
+		if received.truncated {
+			result.Verdict = VerdictDown
+			result.Error = fmt.Sprintf("response body exceeded %d bytes", limit)
+			return result, nil
+		}
+
+		expected, err := normalizeJSON(config.Task.ExpectedResponse)
+		if err != nil {
+			return nil, fmt.Errorf("error reading expected response: %w", err)
+		}
+
+		if !reflect.DeepEqual(expected, r) {
+			result.Verdict = VerdictDown
+			result.Error = "response body did not match expected response"
+			return result, nil
+		}
+	}
+
+	if len(config.Task.Assertions) > 0 {
+		// scheduled jobs carry their assertions compiled
+		programs := config.assertions
+		if programs == nil {
+			if programs, err = compileAssertions(config.Task.Assertions); err != nil {
+				return nil, err
+			}
+		}
+
+		env := assertionEnv(response, r, latency)
+		if err := evaluateAssertions(programs, env); err != nil {
+			result.Verdict = VerdictDown
+			result.Error = err.Error()
+			return result, nil
+		}
+	}
+
+	if config.Task.Script != nil {
+		start := time.Now()
+		outcome, err := runScript(ctx, config.Task.Script, &scriptResponse{
+			status:  response.StatusCode,
+			headers: response.Header,
+			body:    received.raw,
+			decoded: r,
+			latency: latency,
+		})
+		result.Script = &ScriptResult{Duration: time.Since(start)}
+		if err != nil {
+			result.Verdict = VerdictDown
+			result.Error = fmt.Sprintf("script error: %s", err)
+			return result, nil
+		}
+
+		result.Script.Metrics = outcome.metrics
+		if outcome.verdict != VerdictUp {
+			result.Verdict = outcome.verdict
+			result.Error = outcome.message
+		}
+	}
+	return result, nil
+}
+
+func (h *httpChecker) retryWithDigest(client *http.Client, request *http.Request, response *http.Response, task Task) (*http.Response, error) {
+	challenges := parseDigestChallenges(response.Header)
+	if len(challenges) == 0 {
+		return response, nil
+	}
+	io.Copy(io.Discard, io.LimitReader(response.Body, maxDrainBytes))
+	response.Body.Close()
+
+	// the challenge came from the last request when redirects were followed
+	challenged := request
+	if response.Request != nil {
+		challenged = response.Request
+	}
+	var body []byte
+	if challenged.GetBody != nil {
+		body = []byte(task.Body)
+	}
+
+	auth, err := challenges[0].authorization(challenged.Method, challenged.URL.RequestURI(), task.AuthHeader.Username, task.AuthHeader.Password, body)
+	if err != nil {
+		return nil, fmt.Errorf("error answering digest challenge: %w", err)
+	}
+
+	retry := challenged.Clone(request.Context())
+	if challenged.GetBody != nil {
+		if retry.Body, err = challenged.GetBody(); err != nil {
+			return nil, fmt.Errorf("error rewinding request body: %w", err)
+		}
+	}
+	retry.Header.Set("Authorization", auth)
+	response, err = client.Do(retry)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	return response, nil
+}
+
+// normalizeJSON round trips a value through JSON so it compares equal to a
+// decoded response body.
+func normalizeJSON(value any) (any, error) {
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalized any
+	if err := json.Unmarshal(bytes, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
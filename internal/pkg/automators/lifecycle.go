@@ -0,0 +1,142 @@
+package automators
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const leaveTimeout = 2 * time.Second
+
+// Start starts the scheduler, the periodic reconcile loop and the job event
+// watcher. They run until Stop is called or ctx is cancelled. An automator is
+// single use, it cannot be started again once stopped.
+func (a *Automator) Start(ctx context.Context) error {
+	a.lifecycleMu.Lock()
+	defer a.lifecycleMu.Unlock()
+
+	if a.stopped {
+		return fmt.Errorf("automator already stopped")
+	}
+	if a.cancel != nil {
+		return fmt.Errorf("automator already started")
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	a.cancel = cancel
+
+	a.scheduler.StartAsync()
+
+	a.loops.Add(2)
+	go func() {
+		defer a.loops.Done()
+		a.watchJobEvents(runCtx)
+	}()
+	go func() {
+		defer a.loops.Done()
+		a.reconcileLoop(runCtx)
+	}()
+
+	a.logger.Infow("automator started", "replica_id", a.replicaID, "reconcile_interval", a.reconcileInterval.String())
+	return nil
+}
+
+// Stop stops accepting new check runs and stops the reconcile loop and event
+// watcher, waits for an in-flight reconcile to finish and then drains running
+// checks for up to the shutdown grace period. When ctx expires first the
+// remaining steps still run and the first error is returned at the end.
+func (a *Automator) Stop(ctx context.Context) error {
+	a.lifecycleMu.Lock()
+	defer a.lifecycleMu.Unlock()
+
+	if a.cancel == nil {
+		return nil
+	}
+	a.stopAcceptingRuns()
+
+	a.cancel()
+	a.cancel = nil
+	a.stopped = true
+
+	var stopErr error
+	if err := waitContext(ctx, a.loops.Wait); err != nil {
+		stopErr = fmt.Errorf("error waiting for reconcile to finish: %w", err)
+		a.logger.Error(stopErr)
+	} else {
+		a.logger.Info("reconcile loop stopped")
+	}
+
+	// leaving the ring must not be skipped because the deadline has passed,
+	// other replicas would keep routing this replica's jobs to it
+	leaveCtx := ctx
+	if ctx.Err() != nil {
+		var cancel context.CancelFunc
+		leaveCtx, cancel = context.WithTimeout(context.Background(), leaveTimeout)
+		defer cancel()
+	}
+	// a reconcile still finishing after the deadline would register the
+	// replica again once it has left
+	if stopErr != nil {
+		if err := waitContext(leaveCtx, a.loops.Wait); err != nil {
+			a.logger.Errorf("leaving while reconcile is still running: %s", err)
+		}
+	}
+	if err := a.leave(leaveCtx); err != nil {
+		a.logger.Error(err)
+	}
+
+	drainErr := a.drainRuns(ctx)
+	if drainErr != nil {
+		a.logger.Warnf("cancelled running checks: %s", drainErr)
+		if stopErr == nil {
+			stopErr = drainErr
+		}
+	}
+
+	// the scheduler stops in the background even when ctx has expired
+	if err := waitContext(ctx, a.scheduler.Stop); err != nil {
+		if stopErr == nil {
+			stopErr = fmt.Errorf("error stopping scheduler: %w", err)
+		}
+	} else {
+		a.logger.Info("scheduler stopped")
+	}
+
+	a.transports.CloseIdleConnections()
+	return stopErr
+}
+
+func (a *Automator) reconcileLoop(ctx context.Context) {
+	ticker := time.NewTicker(a.reconcileInterval)
+	defer ticker.Stop()
+
+	// reconciles run to completion on a background context so that Stop waits
+	// for them instead of abandoning them halfway through
+	a.Reconcile(context.Background())
+
+	for {
+		select {
+		case <-ticker.C:
+			a.logger.Debug("start reconcile ticker jobs")
+			a.Reconcile(context.Background())
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func waitContext(ctx context.Context, wait func()) error {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
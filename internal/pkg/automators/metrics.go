@@ -0,0 +1,93 @@
+package automators
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type checkMetrics struct {
+	runs     *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	phases   *prometheus.HistogramVec
+	up       *prometheus.GaugeVec
+	script   *prometheus.GaugeVec
+}
+
+func newCheckMetrics(registerer prometheus.Registerer) *checkMetrics {
+	m := &checkMetrics{
+		runs: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "ping",
+			Name:      "check_runs_total",
+			Help:      "Number of check runs by check type and verdict.",
+		}, []string{"type", "verdict"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "ping",
+			Name:      "check_duration_seconds",
+			Help:      "Total duration of check runs.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type"}),
+		phases: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "ping",
+			Name:      "check_http_phase_duration_seconds",
+			Help:      "Duration of each phase of an HTTP check request.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"type", "phase"}),
+		up: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "ping",
+			Name:      "check_up",
+			Help:      "Whether the last run of a job was up (1) or down (0).",
+		}, []string{"job_uid", "type"}),
+		script: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "ping",
+			Name:      "check_script_metric",
+			Help:      "Custom metrics returned by a job's script on its last run.",
+		}, []string{"job_uid", "name"}),
+	}
+
+	registerer.MustRegister(m.runs, m.duration, m.phases, m.up, m.script)
+	return m
+}
+
+func (m *checkMetrics) observe(config *JobConfig, result *RunResult) {
+	checkType := config.Type
+	if checkType == "" {
+		checkType = CheckTypeHTTP
+	}
+
+	m.runs.WithLabelValues(checkType, string(result.Verdict)).Inc()
+	m.duration.WithLabelValues(checkType).Observe(result.Duration.Seconds())
+
+	up := 0.0
+	if result.Verdict != VerdictDown {
+		up = 1
+	}
+	m.up.WithLabelValues(config.UID.String(), checkType).Set(up)
+
+	if timings := result.Timings; timings != nil {
+		// reused connections and plain http skip the dns, connect and tls
+		// phases, observing them as 0 would skew the histograms
+		for phase, duration := range map[string]time.Duration{
+			"dns":     timings.DNSLookup,
+			"connect": timings.TCPConnect,
+			"tls":     timings.TLSHandshake,
+		} {
+			if duration > 0 {
+				m.phases.WithLabelValues(checkType, phase).Observe(duration.Seconds())
+			}
+		}
+		m.phases.WithLabelValues(checkType, "first_byte").Observe(timings.TimeToFirstByte.Seconds())
+		m.phases.WithLabelValues(checkType, "transfer").Observe(timings.Transfer.Seconds())
+	}
+
+	if result.Script != nil {
+		for name, value := range result.Script.Metrics {
+			m.script.WithLabelValues(config.UID.String(), name).Set(value)
+		}
+	}
+}
+
+func (m *checkMetrics) forget(jobID string) {
+	m.up.DeletePartialMatch(prometheus.Labels{"job_uid": jobID})
+	m.script.DeletePartialMatch(prometheus.Labels{"job_uid": jobID})
+}
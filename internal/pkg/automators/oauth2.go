@@ -0,0 +1,153 @@
+package automators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// refresh tokens this long before they expire so a run never sends a
+	// token that lapses mid-request
+	oauth2ExpiryDelta   = 30 * time.Second
+	defaultOAuth2Expiry = time.Hour
+)
+
+type oauth2Token struct {
+	accessToken string
+	tokenType   string
+	expiresAt   time.Time
+}
+
+type oauth2TokenCache struct {
+	mu     sync.Mutex
+	tokens map[string]*oauth2Token
+}
+
+func newOAuth2TokenCache() *oauth2TokenCache {
+	return &oauth2TokenCache{
+		tokens: make(map[string]*oauth2Token),
+	}
+}
+
+func validateOAuth2(config *OAuth2Config) error {
+	u, err := url.Parse(config.TokenURL)
+	if err != nil {
+		return fmt.Errorf("invalid oauth2 token url: %w", err)
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("oauth2 token url scheme must be http or https")
+	}
+
+	if config.ClientID == "" || config.ClientSecret == "" {
+		return fmt.Errorf("oauth2 requires a client id and secret")
+	}
+	return nil
+}
+
+func (c *oauth2TokenCache) authorization(ctx context.Context, client *http.Client, config *OAuth2Config) (string, error) {
+	key := oauth2CacheKey(config)
+
+	c.mu.Lock()
+	token, ok := c.tokens[key]
+	c.mu.Unlock()
+
+	if !ok || time.Now().Add(oauth2ExpiryDelta).After(token.expiresAt) {
+		var err error
+		token, err = fetchOAuth2Token(ctx, client, config)
+		if err != nil {
+			return "", err
+		}
+
+		c.mu.Lock()
+		c.tokens[key] = token
+		c.mu.Unlock()
+	}
+
+	tokenType := token.tokenType
+	if tokenType == "" || strings.EqualFold(tokenType, "bearer") {
+		tokenType = "Bearer"
+	}
+	return fmt.Sprintf("%s %s", tokenType, token.accessToken), nil
+}
+
+func (c *oauth2TokenCache) invalidate(config *OAuth2Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.tokens, oauth2CacheKey(config))
+}
+
+func fetchOAuth2Token(ctx context.Context, client *http.Client, config *OAuth2Config) (*oauth2Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+	for key, value := range config.EndpointParams {
+		form.Set(key, value)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("error creating token request: %w", err)
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(config.ClientID), url.QueryEscape(config.ClientSecret))
+
+	start := time.Now()
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error requesting oauth2 token: %w", err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("error reading oauth2 token response: %w", err)
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oauth2 token endpoint returned status %d", response.StatusCode)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		TokenType   string `json:"token_type"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("error decoding oauth2 token response: %w", err)
+	}
+
+	if payload.AccessToken == "" {
+		return nil, fmt.Errorf("oauth2 token response has no access token")
+	}
+
+	expiry := defaultOAuth2Expiry
+	if payload.ExpiresIn > 0 {
+		expiry = time.Duration(payload.ExpiresIn) * time.Second
+	}
+
+	return &oauth2Token{
+		accessToken: payload.AccessToken,
+		tokenType:   payload.TokenType,
+		expiresAt:   start.Add(expiry),
+	}, nil
+}
+
+// tokens are shared between jobs that use identical client credentials
+func oauth2CacheKey(config *OAuth2Config) string {
+	bytes, _ := json.Marshal(config)
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,201 @@
+package automators_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func startOAuth2Server(t *testing.T, expiresIn int) (*httptest.Server, *int32) {
+	t.Helper()
+
+	var issued int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "ping-app" || secret != "s3cret" || r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		n := atomic.AddInt32(&issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, expiresIn)
+	})
+	mux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", atomic.LoadInt32(&issued)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func TestHTTPChecker_OAuth2(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		expiresIn   int
+		secret      string
+		runs        int
+		wantIssued  int32
+		wantVerdict automators.Verdict
+		wantErr     bool
+	}{
+		{
+			name:        "token cached between runs",
+			expiresIn:   3600,
+			secret:      "s3cret",
+			runs:        3,
+			wantIssued:  1,
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "token refreshed before expiry",
+			expiresIn:   10,
+			secret:      "s3cret",
+			runs:        3,
+			wantIssued:  3,
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:       "token endpoint rejects client",
+			expiresIn:  3600,
+			secret:     "wrong",
+			runs:       1,
+			wantIssued: 0,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server, issued := startOAuth2Server(t, tt.expiresIn)
+			checker := automators.NewHTTPChecker()
+			config := &automators.JobConfig{
+				Task: automators.Task{
+					URL: server.URL + "/ping",
+					OAuth2: &automators.OAuth2Config{
+						TokenURL:     server.URL + "/token",
+						ClientID:     "ping-app",
+						ClientSecret: tt.secret,
+						Scopes:       []string{"health.read"},
+					},
+				},
+			}
+
+			for i := 0; i < tt.runs; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				got, err := checker.Execute(ctx, config)
+				cancel()
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("httpChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					break
+				}
+				if got.Verdict != tt.wantVerdict {
+					t.Errorf("httpChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+				}
+			}
+
+			if got := atomic.LoadInt32(issued); got != tt.wantIssued {
+				t.Errorf("token endpoint issued %d tokens, want %d", got, tt.wantIssued)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_OAuth2RejectedTokenRefetched(t *testing.T) {
+	t.Parallel()
+
+	server, issued := startOAuth2Server(t, 3600)
+	checker := automators.NewHTTPChecker()
+	oauth2 := &automators.OAuth2Config{
+		TokenURL:     server.URL + "/token",
+		ClientID:     "ping-app",
+		ClientSecret: "s3cret",
+	}
+
+	// a different job sharing the credentials rotates the token behind the
+	// cached one, so the next run is rejected and must refetch
+	if _, err := checker.Execute(context.Background(), &automators.JobConfig{Task: automators.Task{URL: server.URL + "/ping", OAuth2: oauth2}}); err != nil {
+		t.Fatal(err)
+	}
+	atomic.AddInt32(issued, 1)
+
+	got, err := checker.Execute(context.Background(), &automators.JobConfig{Task: automators.Task{URL: server.URL + "/ping", OAuth2: oauth2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Verdict != automators.VerdictDown {
+		t.Fatalf("httpChecker.Execute() verdict = %v, want down with stale token", got.Verdict)
+	}
+
+	got, err = checker.Execute(context.Background(), &automators.JobConfig{Task: automators.Task{URL: server.URL + "/ping", OAuth2: oauth2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Verdict != automators.VerdictUp {
+		t.Errorf("httpChecker.Execute() verdict = %v, want up after refetch (%s)", got.Verdict, got.Error)
+	}
+}
+
+func TestHTTPChecker_ValidateOAuth2(t *testing.T) {
+	t.Parallel()
+
+	valid := automators.OAuth2Config{TokenURL: "https://auth.internal/oauth/token", ClientID: "ping-app", ClientSecret: "s3cret"}
+	missingSecret := valid
+	missingSecret.ClientSecret = ""
+	badTokenURL := valid
+	badTokenURL.TokenURL = "auth.internal/token"
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			task:    automators.Task{URL: "https://api.internal/ping", OAuth2: &valid},
+			wantErr: false,
+		},
+		{
+			name:    "missing secret",
+			task:    automators.Task{URL: "https://api.internal/ping", OAuth2: &missingSecret},
+			wantErr: true,
+		},
+		{
+			name:    "invalid token url",
+			task:    automators.Task{URL: "https://api.internal/ping", OAuth2: &badTokenURL},
+			wantErr: true,
+		},
+		{
+			name:    "combined with auth header",
+			task:    automators.Task{URL: "https://api.internal/ping", OAuth2: &valid, AuthHeader: automators.AuthHeader{Scheme: "Bearer", Parameters: "static"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewHTTPChecker().Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("httpChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package automators
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+func validateProxy(config *ProxyConfig) error {
+	if config.Disabled {
+		if config.URL != "" {
+			return fmt.Errorf("proxy url cannot be set when the proxy is disabled")
+		}
+		return nil
+	}
+
+	_, err := parseProxyURL(config)
+	return err
+}
+
+// ValidateProxyURL checks a proxy url given to WithProxy.
+func ValidateProxyURL(proxyURL string) error {
+	_, err := parseProxyURL(&ProxyConfig{URL: proxyURL})
+	return err
+}
+
+// parseProxyURL returns the proxy to use, or nil when the proxy is disabled.
+func parseProxyURL(config *ProxyConfig) (*url.URL, error) {
+	if config == nil || config.Disabled {
+		return nil, nil
+	}
+
+	u, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy url scheme must be http, https or socks5")
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy url has no host")
+	}
+
+	if config.Username != "" {
+		u.User = url.UserPassword(config.Username, config.Password)
+	}
+	return u, nil
+}
+
+// dialContext connects to address directly or through the configured proxy.
+func dialContext(ctx context.Context, config *ProxyConfig, network, address string) (net.Conn, error) {
+	proxyURL, err := parseProxyURL(config)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{}
+	if proxyURL == nil {
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+
+		socks, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, dialer)
+		if err != nil {
+			return nil, fmt.Errorf("error creating socks5 dialer: %w", err)
+		}
+		return socks.(proxy.ContextDialer).DialContext(ctx, network, address)
+	default:
+		return dialConnect(ctx, dialer, proxyURL, address)
+	}
+}
+
+// dialConnect opens a tunnel to address through an HTTP proxy using CONNECT.
+func dialConnect(ctx context.Context, dialer *net.Dialer, proxyURL *url.URL, address string) (net.Conn, error) {
+	conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to proxy: %w", err)
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error performing tls handshake with proxy: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	request := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		request.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if err := request.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error sending proxy connect: %w", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error reading proxy connect response: %w", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy refused connect: %s", response.Status)
+	}
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn replays bytes the target sent straight after the CONNECT
+// response, which the response reader may already have consumed.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,416 @@
+package automators_test
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/mock"
+)
+
+func pipe(a, b net.Conn) {
+	done := make(chan struct{}, 2)
+	go func() {
+		io.Copy(a, b)
+		done <- struct{}{}
+	}()
+	go func() {
+		io.Copy(b, a)
+		done <- struct{}{}
+	}()
+	<-done
+}
+
+// startHTTPProxy starts a forward proxy that tunnels CONNECT requests and
+// forwards plain requests. It counts every request it serves.
+func startHTTPProxy(t *testing.T, username, password string) (string, *int32) {
+	t.Helper()
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if username != "" {
+			want := "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+			if r.Header.Get("Proxy-Authorization") != want {
+				w.WriteHeader(http.StatusProxyAuthRequired)
+				return
+			}
+		}
+		atomic.AddInt32(&requests, 1)
+
+		if r.Method != http.MethodConnect {
+			r.RequestURI = ""
+			r.Header.Del("Proxy-Authorization")
+			response, err := http.DefaultTransport.RoundTrip(r)
+			if err != nil {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			defer response.Body.Close()
+			for key, values := range response.Header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(response.StatusCode)
+			io.Copy(w, response.Body)
+			return
+		}
+
+		target, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer target.Close()
+
+		conn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("HTTP/1.1 200 Connection established\r\n\r\n"))
+		pipe(conn, target)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL, &requests
+}
+
+// startSOCKS5Proxy starts a SOCKS5 proxy without authentication that only
+// supports CONNECT.
+func startSOCKS5Proxy(t *testing.T) (string, *int32) {
+	t.Helper()
+
+	var requests int32
+	address := startTCPServer(t, func(conn net.Conn) {
+		greeting := make([]byte, 2)
+		if _, err := io.ReadFull(conn, greeting); err != nil {
+			return
+		}
+		if _, err := io.ReadFull(conn, make([]byte, greeting[1])); err != nil {
+			return
+		}
+		conn.Write([]byte{0x05, 0x00})
+
+		header := make([]byte, 4)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+
+		var host string
+		switch header[3] {
+		case 0x01:
+			ip := make([]byte, net.IPv4len)
+			io.ReadFull(conn, ip)
+			host = net.IP(ip).String()
+		case 0x03:
+			length := make([]byte, 1)
+			io.ReadFull(conn, length)
+			name := make([]byte, length[0])
+			io.ReadFull(conn, name)
+			host = string(name)
+		case 0x04:
+			ip := make([]byte, net.IPv6len)
+			io.ReadFull(conn, ip)
+			host = net.IP(ip).String()
+		default:
+			return
+		}
+		port := make([]byte, 2)
+		if _, err := io.ReadFull(conn, port); err != nil {
+			return
+		}
+
+		target, err := net.Dial("tcp", net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))))
+		if err != nil {
+			conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+			return
+		}
+		defer target.Close()
+		atomic.AddInt32(&requests, 1)
+
+		conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+		pipe(conn, target)
+	})
+
+	return "socks5://" + address, &requests
+}
+
+func echoServer(t *testing.T) string {
+	t.Helper()
+
+	return startTCPServer(t, func(conn net.Conn) {
+		io.Copy(conn, conn)
+	})
+}
+
+func TestTCPChecker_Proxy(t *testing.T) {
+	t.Parallel()
+
+	target := echoServer(t)
+	httpProxy, httpRequests := startHTTPProxy(t, "", "")
+	authProxy, authRequests := startHTTPProxy(t, "monitor", "s3cret")
+	socksProxy, socksRequests := startSOCKS5Proxy(t)
+
+	tests := []struct {
+		name     string
+		proxy    *automators.ProxyConfig
+		requests *int32
+		wantErr  bool
+	}{
+		{
+			name:     "http connect proxy",
+			proxy:    &automators.ProxyConfig{URL: httpProxy},
+			requests: httpRequests,
+		},
+		{
+			name:     "authenticated http proxy",
+			proxy:    &automators.ProxyConfig{URL: authProxy, Username: "monitor", Password: "s3cret"},
+			requests: authRequests,
+		},
+		{
+			name:    "wrong proxy credentials",
+			proxy:   &automators.ProxyConfig{URL: authProxy, Username: "monitor", Password: "wrong"},
+			wantErr: true,
+		},
+		{
+			name:     "socks5 proxy",
+			proxy:    &automators.ProxyConfig{URL: socksProxy},
+			requests: socksRequests,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			config := &automators.JobConfig{
+				Type: automators.CheckTypeTCP,
+				Task: automators.Task{
+					Proxy: tt.proxy,
+					TCP:   &automators.TCPTask{Address: target, Payload: "ping", Expect: "ping"},
+				},
+			}
+			got, err := automators.NewTCPChecker().Execute(ctx, config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("tcpChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Verdict != automators.VerdictUp {
+				t.Errorf("tcpChecker.Execute() verdict = %s, error = %s", got.Verdict, got.Error)
+			}
+			if atomic.LoadInt32(tt.requests) == 0 {
+				t.Error("connection did not go through the proxy")
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_Proxy(t *testing.T) {
+	t.Parallel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	plain := httptest.NewServer(handler)
+	t.Cleanup(plain.Close)
+	secure := httptest.NewTLSServer(handler)
+	t.Cleanup(secure.Close)
+
+	tests := []struct {
+		name         string
+		task         func(proxyURL string) automators.Task
+		wantRequests bool
+	}{
+		{
+			name: "plain http through proxy",
+			task: func(proxyURL string) automators.Task {
+				return automators.Task{URL: plain.URL, Proxy: &automators.ProxyConfig{URL: proxyURL}}
+			},
+			wantRequests: true,
+		},
+		{
+			name: "https tunnelled through proxy",
+			task: func(proxyURL string) automators.Task {
+				return automators.Task{
+					URL:   secure.URL,
+					TLS:   &automators.TLSOptions{InsecureSkipVerify: true},
+					Proxy: &automators.ProxyConfig{URL: proxyURL},
+				}
+			},
+			wantRequests: true,
+		},
+		{
+			name: "disabled proxy",
+			task: func(proxyURL string) automators.Task {
+				return automators.Task{URL: plain.URL, Proxy: &automators.ProxyConfig{Disabled: true}}
+			},
+			wantRequests: false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			proxyURL, requests := startHTTPProxy(t, "", "")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: tt.task(proxyURL)})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+			if got.Verdict != automators.VerdictUp {
+				t.Errorf("httpChecker.Execute() verdict = %s, error = %s", got.Verdict, got.Error)
+			}
+			if proxied := atomic.LoadInt32(requests) > 0; proxied != tt.wantRequests {
+				t.Errorf("request proxied = %v, want %v", proxied, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDNSChecker_Proxy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		resolver string
+	}{
+		{
+			name:     "system resolver",
+			resolver: "",
+		},
+		{
+			name:     "explicit resolver",
+			resolver: "127.0.0.1:1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			proxyURL, requests := startHTTPProxy(t, "", "")
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			// the lookup itself may fail, only the route it took matters here
+			config := &automators.JobConfig{
+				Type: automators.CheckTypeDNS,
+				Task: automators.Task{
+					Proxy: &automators.ProxyConfig{URL: proxyURL},
+					DNS:   &automators.DNSTask{Name: "ping-app.test", RecordType: "A", Resolver: tt.resolver},
+				},
+			}
+			automators.NewDNSChecker().Execute(ctx, config)
+
+			if atomic.LoadInt32(requests) == 0 {
+				t.Error("dns lookup did not go through the proxy")
+			}
+		})
+	}
+}
+
+func TestAutomator_GlobalProxy(t *testing.T) {
+	t.Parallel()
+
+	target := echoServer(t)
+	proxyURL, requests := startHTTPProxy(t, "", "")
+
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar(),
+		automators.WithProxy(proxyURL),
+	)
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Stop(ctx)
+
+	if _, err := a.CreateNewJob(ctx, automators.JobConfig{
+		Type:           automators.CheckTypeTCP,
+		CronExpression: "* * * * * *",
+		Task:           automators.Task{TCP: &automators.TCPTask{Address: target}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if atomic.LoadInt32(requests) > 0 {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Error("job did not use the global proxy")
+}
+
+func TestValidateProxyURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		proxyURL string
+		wantErr  bool
+	}{
+		{
+			name:     "http proxy",
+			proxyURL: "http://proxy.internal:3128",
+		},
+		{
+			name:     "socks5 proxy",
+			proxyURL: "socks5://proxy.internal:1080",
+		},
+		{
+			name:     "unsupported scheme",
+			proxyURL: "ftp://proxy.internal:21",
+			wantErr:  true,
+		},
+		{
+			name:     "missing scheme",
+			proxyURL: "proxy.internal:3128",
+			wantErr:  true,
+		},
+		{
+			name:     "missing host",
+			proxyURL: "http://",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.ValidateProxyURL(tt.proxyURL); (err != nil) != tt.wantErr {
+				t.Errorf("ValidateProxyURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package automators
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type ReconcileResult struct {
+	ReplicaID   string        `json:"replica_id"`
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"duration_ns"`
+	Replicas    []string      `json:"replicas"`
+	Added       []string      `json:"added"`
+	Removed     []string      `json:"removed"`
+	Rescheduled []string      `json:"rescheduled"`
+	Errors      []string      `json:"errors,omitempty"`
+}
+
+// Reconcile brings the local scheduler in line with the jobs stored in the
+// cache: owned jobs that are missing are added, jobs that were deleted or are
+// owned by another replica are removed and jobs whose stored config changed
+// are rescheduled.
+func (a *Automator) Reconcile(ctx context.Context) *ReconcileResult {
+	result := &ReconcileResult{
+		ReplicaID:   a.replicaID,
+		StartedAt:   time.Now(),
+		Added:       make([]string, 0),
+		Removed:     make([]string, 0),
+		Rescheduled: make([]string, 0),
+	}
+	defer func() {
+		result.Duration = time.Since(result.StartedAt)
+		a.reconcileMu.Lock()
+		a.lastReconcile = result
+		a.reconcileMu.Unlock()
+	}()
+
+	if err := a.heartbeat(ctx); err != nil {
+		a.logger.Errorf("error sending replica heartbeat: %s", err)
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+	result.Replicas = a.replicas()
+
+	jobSet, err := a.cache.GetSet(ctx, a.jobSetName)
+	if err != nil {
+		a.logger.Errorf("error getting job set: %s", err)
+		result.Errors = append(result.Errors, err.Error())
+		return result
+	}
+
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+
+	tags := make(map[string]struct{}, 0)
+
+	for _, job := range a.scheduler.Jobs() {
+		for _, tag := range job.Tags() {
+			tags[tag] = struct{}{}
+		}
+	}
+
+	for tag := range tags {
+		_, stored := jobSet[tag]
+		if stored && a.ownsJob(tag) {
+			continue
+		}
+
+		if err := a.unscheduleJob(tag); err != nil {
+			a.logger.Errorw("error removing job", "job_id", tag, "error", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", tag, err))
+			continue
+		}
+		result.Removed = append(result.Removed, tag)
+	}
+
+	for key := range jobSet {
+		if !a.ownsJob(key) {
+			continue
+		}
+
+		data, err := a.cache.GetData(ctx, key)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", key, err))
+			continue
+		}
+
+		config, err := DecryptJobInfo(a.secretKey, data)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", key, err))
+			continue
+		}
+
+		hash, err := configHash(config)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", key, err))
+			continue
+		}
+
+		_, scheduled := tags[key]
+		if scheduled && a.scheduledHashes[key] == hash {
+			continue
+		}
+
+		if scheduled {
+			if err := a.unscheduleJob(key); err != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", key, err))
+				continue
+			}
+		}
+
+		if err := a.scheduleJob(config); err != nil {
+			a.logger.Errorw("error scheduling job", "job_id", key, "error", err)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %s", key, err))
+			continue
+		}
+
+		if scheduled {
+			result.Rescheduled = append(result.Rescheduled, key)
+		} else {
+			result.Added = append(result.Added, key)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Strings(result.Rescheduled)
+
+	if len(result.Added) > 0 || len(result.Removed) > 0 || len(result.Rescheduled) > 0 {
+		a.logger.Infow("reconciled jobs", "added", result.Added, "removed", result.Removed, "rescheduled", result.Rescheduled)
+	}
+	return result
+}
+
+func (a *Automator) LastReconcile() *ReconcileResult {
+	a.reconcileMu.RLock()
+	defer a.reconcileMu.RUnlock()
+
+	if a.lastReconcile == nil {
+		return nil
+	}
+
+	result := *a.lastReconcile
+	return &result
+}
+
+func configHash(config *JobConfig) (string, error) {
+	bytes, err := json.Marshal(config)
+	if err != nil {
+		return "", fmt.Errorf("error marshalling job config: %w", err)
+	}
+
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
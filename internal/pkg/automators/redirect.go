@@ -0,0 +1,130 @@
+package automators
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+const (
+	RedirectFollow = "follow"
+	RedirectNone   = "none"
+
+	defaultMaxRedirects = 10
+)
+
+var errTooManyRedirects = errors.New("too many redirects")
+
+func validateRedirectPolicy(policy *RedirectPolicy) error {
+	switch policy.Mode {
+	case "", RedirectFollow:
+	case RedirectNone:
+		if policy.MaxHops != 0 {
+			return fmt.Errorf("max hops cannot be set when redirects are not followed")
+		}
+	default:
+		return fmt.Errorf("redirect mode must be follow or none")
+	}
+
+	if policy.MaxHops < 0 {
+		return fmt.Errorf("max hops cannot be negative")
+	}
+
+	expected := append([]string{}, policy.ExpectedChain...)
+	if policy.ExpectedFinalURL != "" {
+		expected = append(expected, policy.ExpectedFinalURL)
+	}
+	for _, rawURL := range expected {
+		u, err := url.Parse(rawURL)
+		if err != nil || !u.IsAbs() {
+			return fmt.Errorf("expected redirect url %q must be an absolute url", rawURL)
+		}
+	}
+	return nil
+}
+
+// redirectRecorder applies a job's redirect policy and records every redirect
+// response the request receives, including one that is not followed.
+type redirectRecorder struct {
+	policy *RedirectPolicy
+	chain  []RedirectHop
+}
+
+func newRedirectRecorder(policy *RedirectPolicy) *redirectRecorder {
+	return &redirectRecorder{policy: policy}
+}
+
+func (r *redirectRecorder) checkRedirect(request *http.Request, via []*http.Request) error {
+	hop := RedirectHop{
+		URL:      via[len(via)-1].URL.String(),
+		Location: request.URL.String(),
+	}
+	if request.Response != nil {
+		hop.StatusCode = request.Response.StatusCode
+	}
+	r.chain = append(r.chain, hop)
+
+	if r.policy != nil && r.policy.Mode == RedirectNone {
+		return http.ErrUseLastResponse
+	}
+
+	if len(via) > r.maxHops() {
+		return fmt.Errorf("%w: stopped after %d redirects", errTooManyRedirects, r.maxHops())
+	}
+	return nil
+}
+
+func (r *redirectRecorder) maxHops() int {
+	if r.policy == nil || r.policy.MaxHops == 0 {
+		return defaultMaxRedirects
+	}
+	return r.policy.MaxHops
+}
+
+// result is nil when the job has no redirect policy and was not redirected.
+func (r *redirectRecorder) result(response *http.Response) *RedirectResult {
+	if r.policy == nil && len(r.chain) == 0 {
+		return nil
+	}
+	return &RedirectResult{
+		FinalURL: response.Request.URL.String(),
+		Chain:    r.chain,
+	}
+}
+
+// evaluateRedirects marks the result down when the final url or the
+// locations redirected to differ from the policy's expectations.
+func evaluateRedirects(result *RunResult, policy *RedirectPolicy) {
+	redirects := result.Redirects
+	if policy == nil || redirects == nil {
+		return
+	}
+
+	if policy.ExpectedFinalURL != "" && redirects.FinalURL != policy.ExpectedFinalURL {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("final url %s, expected %s", redirects.FinalURL, policy.ExpectedFinalURL)
+		return
+	}
+
+	if len(policy.ExpectedChain) == 0 {
+		return
+	}
+
+	locations := make([]string, 0, len(redirects.Chain))
+	for _, hop := range redirects.Chain {
+		locations = append(locations, hop.Location)
+	}
+	if len(locations) != len(policy.ExpectedChain) {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("redirected %d times, expected %d", len(locations), len(policy.ExpectedChain))
+		return
+	}
+	for i, location := range locations {
+		if location != policy.ExpectedChain[i] {
+			result.Verdict = VerdictDown
+			result.Error = fmt.Sprintf("redirect %d went to %s, expected %s", i+1, location, policy.ExpectedChain[i])
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package automators_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func TestHTTPChecker_Redirects(t *testing.T) {
+	t.Parallel()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/middle", http.StatusMovedPermanently)
+	})
+	mux.HandleFunc("/middle", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/end", http.StatusFound)
+	})
+	mux.HandleFunc("/end", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name         string
+		path         string
+		policy       *automators.RedirectPolicy
+		wantVerdict  automators.Verdict
+		wantStatus   int
+		wantFinalURL string
+		wantHops     int
+	}{
+		{
+			name:         "follows redirects by default",
+			path:         "/start",
+			wantVerdict:  automators.VerdictUp,
+			wantStatus:   http.StatusOK,
+			wantFinalURL: server.URL + "/end",
+			wantHops:     2,
+		},
+		{
+			name:         "does not follow redirects",
+			path:         "/start",
+			policy:       &automators.RedirectPolicy{Mode: automators.RedirectNone},
+			wantVerdict:  automators.VerdictUp,
+			wantStatus:   http.StatusMovedPermanently,
+			wantFinalURL: server.URL + "/start",
+			wantHops:     1,
+		},
+		{
+			name:         "too many hops",
+			path:         "/start",
+			policy:       &automators.RedirectPolicy{MaxHops: 1},
+			wantVerdict:  automators.VerdictDown,
+			wantStatus:   http.StatusFound,
+			wantFinalURL: server.URL + "/middle",
+			wantHops:     2,
+		},
+		{
+			name:         "matching final url and chain",
+			path:         "/start",
+			policy:       &automators.RedirectPolicy{ExpectedFinalURL: server.URL + "/end", ExpectedChain: []string{server.URL + "/middle", server.URL + "/end"}},
+			wantVerdict:  automators.VerdictUp,
+			wantStatus:   http.StatusOK,
+			wantFinalURL: server.URL + "/end",
+			wantHops:     2,
+		},
+		{
+			name:         "unexpected final url",
+			path:         "/start",
+			policy:       &automators.RedirectPolicy{ExpectedFinalURL: server.URL + "/middle"},
+			wantVerdict:  automators.VerdictDown,
+			wantStatus:   http.StatusOK,
+			wantFinalURL: server.URL + "/end",
+			wantHops:     2,
+		},
+		{
+			name:         "unexpected chain",
+			path:         "/start",
+			policy:       &automators.RedirectPolicy{ExpectedChain: []string{server.URL + "/end"}},
+			wantVerdict:  automators.VerdictDown,
+			wantStatus:   http.StatusOK,
+			wantFinalURL: server.URL + "/end",
+			wantHops:     2,
+		},
+		{
+			name:        "no redirects recorded without a policy",
+			path:        "/end",
+			wantVerdict: automators.VerdictUp,
+			wantStatus:  http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			task := automators.Task{URL: server.URL + tt.path, Redirect: tt.policy}
+			if err := automators.NewHTTPChecker().Validate(task); err != nil {
+				t.Fatalf("httpChecker.Validate() error = %v", err)
+			}
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: task})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("httpChecker.Execute() verdict = %s, want %s (error %q)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if got.StatusCode != tt.wantStatus {
+				t.Errorf("httpChecker.Execute() status = %d, want %d", got.StatusCode, tt.wantStatus)
+			}
+
+			if tt.wantFinalURL == "" {
+				if got.Redirects != nil {
+					t.Errorf("httpChecker.Execute() redirects = %+v, want none", got.Redirects)
+				}
+				return
+			}
+			if got.Redirects == nil {
+				t.Fatal("httpChecker.Execute() recorded no redirects")
+			}
+			if got.Redirects.FinalURL != tt.wantFinalURL {
+				t.Errorf("final url = %s, want %s", got.Redirects.FinalURL, tt.wantFinalURL)
+			}
+			if len(got.Redirects.Chain) != tt.wantHops {
+				t.Errorf("redirect chain = %+v, want %d hops", got.Redirects.Chain, tt.wantHops)
+			}
+		})
+	}
+}
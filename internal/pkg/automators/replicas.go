@@ -0,0 +1,75 @@
+package automators
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/hashring"
+)
+
+const (
+	replicaTTLIntervals = 3
+	replicaVirtualNodes = 128
+)
+
+// heartbeat records this replica as alive, prunes replicas that stopped
+// heartbeating and rebuilds the hash ring when membership changed.
+func (a *Automator) heartbeat(ctx context.Context) error {
+	now := time.Now()
+	cutoff := float64(now.Add(-replicaTTLIntervals * a.reconcileInterval).Unix())
+
+	if err := a.cache.UpdateSortedSet(ctx, a.replicaSetName, a.replicaID, float64(now.Unix())); err != nil {
+		return fmt.Errorf("error updating replica heartbeat: %w", err)
+	}
+
+	if err := a.cache.DeleteFromSortedSetByScore(ctx, a.replicaSetName, math.Inf(-1), cutoff); err != nil {
+		return fmt.Errorf("error pruning stale replicas: %w", err)
+	}
+
+	members, err := a.cache.GetSortedSetByScore(ctx, a.replicaSetName, cutoff, math.Inf(1))
+	if err != nil {
+		return fmt.Errorf("error retrieving live replicas: %w", err)
+	}
+
+	a.ringMu.Lock()
+	defer a.ringMu.Unlock()
+
+	if a.ring != nil && a.ring.Equal(members) {
+		return nil
+	}
+
+	a.ring = hashring.New(replicaVirtualNodes, members...)
+	a.logger.Infow("replica membership changed", "replica_id", a.replicaID, "replicas", a.ring.Nodes())
+	return nil
+}
+
+func (a *Automator) leave(ctx context.Context) error {
+	if err := a.cache.DeleteFromSortedSet(ctx, a.replicaSetName, a.replicaID); err != nil {
+		return fmt.Errorf("error removing replica heartbeat: %w", err)
+	}
+	return nil
+}
+
+// ownsJob reports whether this replica is responsible for scheduling the job.
+// Until the first heartbeat has been made every job is treated as owned.
+func (a *Automator) ownsJob(jobUID string) bool {
+	a.ringMu.RLock()
+	defer a.ringMu.RUnlock()
+
+	if a.ring == nil || len(a.ring.Nodes()) == 0 {
+		return true
+	}
+	return a.ring.Get(jobUID) == a.replicaID
+}
+
+func (a *Automator) replicas() []string {
+	a.ringMu.RLock()
+	defer a.ringMu.RUnlock()
+
+	if a.ring == nil {
+		return nil
+	}
+	return a.ring.Nodes()
+}
@@ -0,0 +1,209 @@
+package automators
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (a *Automator) runJob(config *JobConfig) {
+	if !a.beginRun() {
+		a.logger.Infow("skipping job run while draining", "job_id", config.UID)
+		return
+	}
+	defer a.inFlight.Done()
+
+	// a panicking run must not take the scheduler and the replica down with it
+	defer func() {
+		if r := recover(); r != nil {
+			a.logger.Errorw("job run panicked", "job_id", config.UID, "panic", r)
+		}
+	}()
+
+	result := a.executeJob(a.runCtx, config)
+	result.ReplicaID = a.replicaID
+	a.releaseUnscheduled(config.UID.String())
+
+	// results are saved on a fresh context so a run cut short by shutdown is
+	// still recorded
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if result.ContentChange != nil {
+		if err := a.detectContentChange(ctx, config, result); err != nil {
+			a.logger.Errorw("error detecting content change", "job_id", config.UID, "error", err)
+		}
+	}
+
+	if err := a.saveRunResult(ctx, result); err != nil {
+		a.logger.Errorw("error saving job result", "job_id", config.UID, "error", err)
+	}
+}
+
+func (a *Automator) executeJob(ctx context.Context, config *JobConfig) *RunResult {
+	logger := a.logger.With("job_id", config.UID, "type", config.Type)
+	start := time.Now()
+
+	timeout := config.Task.Timeout
+	if timeout <= 0 {
+		timeout = defaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	result, err := a.execute(ctx, config)
+	if err == nil && result == nil {
+		err = fmt.Errorf("check returned no result")
+	}
+	if err != nil {
+		logger.Error(err)
+		result = &RunResult{Verdict: VerdictDown, Error: err.Error()}
+	}
+
+	result.JobUID = config.UID
+	result.StartedAt = start
+	if result.Duration == 0 {
+		result.Duration = time.Since(start)
+	}
+
+	a.metrics.observe(config, result)
+
+	logger.Infow("job completed", "verdict", result.Verdict, "body", result.Body, "status_code", result.StatusCode, "duration_ns", result.Duration.Nanoseconds())
+	return result
+}
+
+func (a *Automator) execute(ctx context.Context, config *JobConfig) (result *RunResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			result, err = nil, fmt.Errorf("check panicked: %v", r)
+		}
+	}()
+
+	checker, err := a.checkers.Get(config.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	// jobs without their own proxy settings use the global proxy
+	if config.Task.Proxy == nil && a.proxy != nil {
+		withProxy := *config
+		withProxy.Task.Proxy = a.proxy
+		config = &withProxy
+	}
+	return checker.Execute(ctx, config)
+}
+
+func (a *Automator) beginRun() bool {
+	a.runsMu.Lock()
+	defer a.runsMu.Unlock()
+
+	if a.draining {
+		return false
+	}
+	a.inFlight.Add(1)
+	return true
+}
+
+func (a *Automator) stopAcceptingRuns() {
+	a.runsMu.Lock()
+	defer a.runsMu.Unlock()
+	a.draining = true
+}
+
+// drainRuns waits for in-flight runs for at most the shutdown grace period,
+// after which they are cancelled.
+func (a *Automator) drainRuns(ctx context.Context) error {
+	graceCtx, cancel := context.WithTimeout(ctx, a.shutdownGracePeriod)
+	defer cancel()
+
+	if err := waitContext(graceCtx, a.inFlight.Wait); err != nil {
+		a.cancelRuns()
+		return fmt.Errorf("checks still running after grace period of %s: %w", a.shutdownGracePeriod, err)
+	}
+	return nil
+}
+
+func (a *Automator) saveRunResult(ctx context.Context, result *RunResult) error {
+	bytes, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("error marshalling job result: %w", err)
+	}
+
+	if len(bytes) > maxStoredResultBytes {
+		bytes, err = json.Marshal(summarizeRunResult(result))
+		if err != nil {
+			return fmt.Errorf("error marshalling job result: %w", err)
+		}
+	}
+
+	if err := a.cache.PushToList(ctx, runResultsKey(result.JobUID.String()), string(bytes), runResultHistory); err != nil {
+		return fmt.Errorf("error saving job result: %w", err)
+	}
+	return nil
+}
+
+func summarizeRunResult(result *RunResult) *RunResult {
+	message := result.Error
+	if len(message) > maxStoredErrorBytes {
+		// the cut may land inside a multi-byte character
+		message = strings.ToValidUTF8(message[:maxStoredErrorBytes], "")
+	}
+
+	return &RunResult{
+		JobUID:     result.JobUID,
+		ReplicaID:  result.ReplicaID,
+		StartedAt:  result.StartedAt,
+		Duration:   result.Duration,
+		Verdict:    result.Verdict,
+		StatusCode: result.StatusCode,
+		Error:      message,
+		Truncated:  true,
+	}
+}
+
+func (a *Automator) GetJobResults(ctx context.Context, jobUID uuid.UUID, limit int64) ([]*RunResult, error) {
+	logger := a.logger.With("context", ctx)
+	if limit <= 0 || limit > runResultHistory {
+		limit = runResultHistory
+	}
+
+	data, err := a.cache.GetList(ctx, runResultsKey(jobUID.String()), 0, limit-1)
+	if err != nil {
+		err := fmt.Errorf("error retrieving job results: %w", err)
+		logger.Error(err)
+		return nil, err
+	}
+
+	results := make([]*RunResult, 0, len(data))
+	for _, d := range data {
+		var result RunResult
+		if err := json.Unmarshal([]byte(d), &result); err != nil {
+			err := fmt.Errorf("error unmarshalling job result: %w", err)
+			logger.Error(err)
+			return nil, err
+		}
+		results = append(results, &result)
+	}
+	return results, nil
+}
+
+func runResultsKey(jobUID string) string {
+	return fmt.Sprintf("job_results:%s", jobUID)
+}
+
+// releaseUnscheduled drops the transport a run took for a job that was
+// unscheduled while the run was in flight. The run registers the job with the
+// pool again after unscheduleJob released it, which would otherwise keep the
+// transport open for good.
+func (a *Automator) releaseUnscheduled(jobUID string) {
+	a.jobsMu.Lock()
+	defer a.jobsMu.Unlock()
+
+	if _, ok := a.scheduledHashes[jobUID]; !ok {
+		a.transports.Release(jobUID)
+	}
+}
@@ -0,0 +1,342 @@
+package automators
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExtractJSON   = "json"
+	ExtractHeader = "header"
+	ExtractRegex  = "regex"
+)
+
+var scenarioVariablePattern = regexp.MustCompile(`{{\s*([A-Za-z0-9_.-]+)\s*}}`)
+
+type scenarioChecker struct {
+	transports *TransportPool
+}
+
+func NewScenarioChecker() Checker {
+	return NewScenarioCheckerWithTransports(NewTransportPool(TransportSettings{}))
+}
+
+func NewScenarioCheckerWithTransports(transports *TransportPool) Checker {
+	return &scenarioChecker{transports: transports}
+}
+
+func (s *scenarioChecker) Validate(task Task) error {
+	if task.Scenario == nil || len(task.Scenario.Steps) == 0 {
+		return fmt.Errorf("scenario check requires at least one step")
+	}
+
+	// steps build their own requests, so http check options would be ignored
+	switch {
+	case task.AuthHeader.Scheme != "" || task.OAuth2 != nil || task.Signing != nil:
+		return fmt.Errorf("scenario checks do not support authentication options, set headers on the steps")
+	case task.Redirect != nil:
+		return fmt.Errorf("scenario checks do not support a redirect policy")
+	case task.ResponseBody != nil || task.ContentChange != nil || task.Certificate != nil:
+		return fmt.Errorf("scenario checks do not support response body, content change or certificate options")
+	case len(task.Assertions) > 0 || task.Script != nil:
+		return fmt.Errorf("scenario checks do not support assertions or scripts, use step expectations")
+	}
+
+	for i, step := range task.Scenario.Steps {
+		if step.URL == "" {
+			return fmt.Errorf("step %d has no url", i+1)
+		}
+
+		if !strings.HasPrefix(step.URL, "{{") && !strings.HasPrefix(step.URL, "http://") && !strings.HasPrefix(step.URL, "https://") {
+			return fmt.Errorf("step %d url scheme must be http or https", i+1)
+		}
+
+		for _, extract := range step.Extract {
+			if extract.Variable == "" {
+				return fmt.Errorf("step %d extraction has no variable name", i+1)
+			}
+
+			switch extract.Source {
+			case ExtractJSON, ExtractHeader:
+				if extract.Path == "" {
+					return fmt.Errorf("step %d extraction %q has no path", i+1, extract.Variable)
+				}
+			case ExtractRegex:
+				if _, err := regexp.Compile(extract.Path); err != nil {
+					return fmt.Errorf("step %d extraction %q has invalid regex: %w", i+1, extract.Variable, err)
+				}
+			default:
+				return fmt.Errorf("step %d extraction %q has unknown source %q", i+1, extract.Variable, extract.Source)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *scenarioChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.Scenario
+	if task == nil {
+		return nil, fmt.Errorf("scenario check requires at least one step")
+	}
+
+	client, release, err := s.transports.Client(config.UID.String(), &config.Task)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	variables := make(map[string]string, len(task.Variables))
+	for name, value := range task.Variables {
+		variables[name] = value
+	}
+
+	result := &RunResult{
+		Verdict:  VerdictUp,
+		Scenario: &ScenarioResult{},
+	}
+
+	for i, step := range task.Steps {
+		name := step.Name
+		if name == "" {
+			name = fmt.Sprintf("step %d", i+1)
+		}
+
+		stepResult := runScenarioStep(ctx, client, step, variables)
+		stepResult.Name = name
+		result.Scenario.Steps = append(result.Scenario.Steps, *stepResult)
+		result.StatusCode = stepResult.StatusCode
+
+		if stepResult.Verdict == VerdictDown {
+			result.Verdict = VerdictDown
+			result.Error = fmt.Sprintf("%s: %s", name, stepResult.Error)
+			break
+		}
+	}
+	return result, nil
+}
+
+func runScenarioStep(ctx context.Context, client *http.Client, step ScenarioStep, variables map[string]string) *StepResult {
+	result := &StepResult{Verdict: VerdictDown}
+	start := time.Now()
+	defer func() { result.Duration = time.Since(start) }()
+
+	url, err := interpolate(step.URL, variables, nil)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var escape func(string) string
+	if isJSONStepBody(step) {
+		escape = escapeJSONString
+	}
+	body, err := interpolate(step.Body, variables, escape)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	method := step.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader(body))
+	if err != nil {
+		result.Error = fmt.Sprintf("error creating request: %s", err)
+		return result
+	}
+
+	for key, value := range step.Headers {
+		value, err := interpolate(value, variables, nil)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		request.Header.Set(key, value)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		result.Error = fmt.Sprintf("error making request: %s", err)
+		return result
+	}
+	defer response.Body.Close()
+
+	result.StatusCode = response.StatusCode
+
+	received, err := readResponseBody(response, maxResponseBodyBytes)
+	if err != nil {
+		result.Error = fmt.Sprintf("error reading response: %s", err)
+		return result
+	}
+	raw := received.raw
+
+	var decoded any
+	if isJSONMediaType(received.mediaType) {
+		if err := json.Unmarshal(raw, &decoded); err != nil {
+			result.Error = fmt.Sprintf("error decoding response: %s", err)
+			return result
+		}
+	}
+
+	if err := assertScenarioStep(step, response.StatusCode, raw, decoded); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	for _, extract := range step.Extract {
+		value, err := extractVariable(extract, response.Header, raw, decoded)
+		if err != nil {
+			result.Error = fmt.Sprintf("error extracting %q: %s", extract.Variable, err)
+			return result
+		}
+		variables[extract.Variable] = value
+	}
+
+	result.Verdict = VerdictUp
+	return result
+}
+
+func assertScenarioStep(step ScenarioStep, statusCode int, raw []byte, decoded any) error {
+	if step.ExpectedStatus != 0 {
+		if statusCode != step.ExpectedStatus {
+			return fmt.Errorf("unexpected status code %d, want %d", statusCode, step.ExpectedStatus)
+		}
+	} else if statusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status code %d", statusCode)
+	}
+
+	if step.BodyContains != "" && !bytes.Contains(raw, []byte(step.BodyContains)) {
+		return fmt.Errorf("response body does not contain %q", step.BodyContains)
+	}
+
+	if step.ExpectedResponse != nil {
+		expected, err := normalizeJSON(step.ExpectedResponse)
+		if err != nil {
+			return fmt.Errorf("error reading expected response: %w", err)
+		}
+
+		if !reflect.DeepEqual(expected, decoded) {
+			return fmt.Errorf("response body did not match expected response")
+		}
+	}
+	return nil
+}
+
+func extractVariable(extract Extraction, header http.Header, raw []byte, decoded any) (string, error) {
+	switch extract.Source {
+	case ExtractHeader:
+		value := header.Get(extract.Path)
+		if value == "" {
+			return "", fmt.Errorf("header %q not present", extract.Path)
+		}
+		return value, nil
+	case ExtractRegex:
+		pattern, err := regexp.Compile(extract.Path)
+		if err != nil {
+			return "", err
+		}
+
+		match := pattern.FindSubmatch(raw)
+		if match == nil {
+			return "", fmt.Errorf("pattern did not match")
+		}
+		if len(match) > 1 {
+			return string(match[1]), nil
+		}
+		return string(match[0]), nil
+	case ExtractJSON:
+		value, err := lookupJSONPath(decoded, extract.Path)
+		if err != nil {
+			return "", err
+		}
+
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		return string(bytes), nil
+	}
+	return "", fmt.Errorf("unknown source %q", extract.Source)
+}
+
+// lookupJSONPath walks a dot separated path such as "data.items.0.id"
+// through a decoded JSON document.
+func lookupJSONPath(document any, path string) (any, error) {
+	current := document
+	for _, segment := range strings.Split(strings.TrimPrefix(path, "$."), ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[segment]
+			if !ok {
+				return nil, fmt.Errorf("path %q not found", path)
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("path %q not found", path)
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+	}
+	return current, nil
+}
+
+// interpolate replaces {{name}} placeholders with variables, passing each
+// value through escape when it is set.
+func interpolate(value string, variables map[string]string, escape func(string) string) (string, error) {
+	var missing string
+	interpolated := scenarioVariablePattern.ReplaceAllStringFunc(value, func(match string) string {
+		name := scenarioVariablePattern.FindStringSubmatch(match)[1]
+		v, ok := variables[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		if escape != nil {
+			return escape(v)
+		}
+		return v
+	})
+
+	if missing != "" {
+		return "", fmt.Errorf("undefined variable %q", missing)
+	}
+	return interpolated, nil
+}
+
+// isJSONStepBody reports whether a step sends JSON, by its Content-Type header
+// or, without one, by the body's first character.
+func isJSONStepBody(step ScenarioStep) bool {
+	for key, value := range step.Headers {
+		if strings.EqualFold(key, "Content-Type") {
+			mediaType, _, err := mime.ParseMediaType(value)
+			return err == nil && isJSONMediaType(mediaType)
+		}
+	}
+
+	body := strings.TrimSpace(step.Body)
+	return strings.HasPrefix(body, "{") || strings.HasPrefix(body, "[")
+}
+
+// escapeJSONString escapes a value for use inside a JSON string, placeholders
+// in JSON bodies are expected to be quoted, e.g. {"user":"{{user}}"}.
+func escapeJSONString(value string) string {
+	quoted, _ := json.Marshal(value)
+	return string(quoted[1 : len(quoted)-1])
+}
@@ -0,0 +1,249 @@
+package automators_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func startScenarioServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		var creds struct {
+			User     string `json:"user"`
+			Password string `json:"password"`
+		}
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&creds) != nil || creds.Password != "hunter2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Session", "session-"+creds.User)
+		w.Write([]byte(`{"data":{"token":"abc123","roles":["admin"]}}`))
+	})
+	mux.HandleFunc("/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc123" || r.Header.Get("X-Session") != "session-ops" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write([]byte("<p>account id=42</p>"))
+	})
+	mux.HandleFunc("/accounts/42", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":42,"status":"active"}`))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestScenarioChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	server := startScenarioServer(t)
+
+	login := automators.ScenarioStep{
+		Name:   "login",
+		Method: http.MethodPost,
+		URL:    "{{base}}/login",
+		Body:   `{"user":"{{user}}","password":"hunter2"}`,
+		Extract: []automators.Extraction{
+			{Variable: "token", Source: automators.ExtractJSON, Path: "data.token"},
+			{Variable: "session", Source: automators.ExtractHeader, Path: "X-Session"},
+		},
+	}
+	profile := automators.ScenarioStep{
+		Name:    "profile",
+		URL:     "{{base}}/profile",
+		Headers: map[string]string{"Authorization": "Bearer {{token}}", "X-Session": "{{session}}"},
+		Extract: []automators.Extraction{
+			{Variable: "account", Source: automators.ExtractRegex, Path: `id=(\d+)`},
+		},
+	}
+	account := automators.ScenarioStep{
+		Name:             "account",
+		URL:              "{{base}}/accounts/{{account}}",
+		ExpectedStatus:   http.StatusOK,
+		ExpectedResponse: map[string]any{"id": 42, "status": "active"},
+	}
+
+	badPassword := login
+	badPassword.Body = `{"user":"{{user}}","password":"wrong"}`
+
+	missingPath := login
+	missingPath.Extract = []automators.Extraction{{Variable: "token", Source: automators.ExtractJSON, Path: "data.missing"}}
+
+	wrongStatus := account
+	wrongStatus.ExpectedStatus = http.StatusCreated
+
+	tests := []struct {
+		name        string
+		steps       []automators.ScenarioStep
+		user        string
+		wantVerdict automators.Verdict
+		wantSteps   []automators.Verdict
+	}{
+		{
+			name:        "full flow",
+			steps:       []automators.ScenarioStep{login, profile, account},
+			wantVerdict: automators.VerdictUp,
+			wantSteps:   []automators.Verdict{automators.VerdictUp, automators.VerdictUp, automators.VerdictUp},
+		},
+		{
+			name:        "failed login stops scenario",
+			steps:       []automators.ScenarioStep{badPassword, profile, account},
+			wantVerdict: automators.VerdictDown,
+			wantSteps:   []automators.Verdict{automators.VerdictDown},
+		},
+		{
+			name:        "missing extraction",
+			steps:       []automators.ScenarioStep{missingPath, profile},
+			wantVerdict: automators.VerdictDown,
+			wantSteps:   []automators.Verdict{automators.VerdictDown},
+		},
+		{
+			name:        "undefined variable",
+			steps:       []automators.ScenarioStep{profile},
+			wantVerdict: automators.VerdictDown,
+			wantSteps:   []automators.Verdict{automators.VerdictDown},
+		},
+		{
+			name:        "json escaped variable",
+			steps:       []automators.ScenarioStep{login},
+			user:        `o"ps\`,
+			wantVerdict: automators.VerdictUp,
+			wantSteps:   []automators.Verdict{automators.VerdictUp},
+		},
+		{
+			name:        "unexpected status",
+			steps:       []automators.ScenarioStep{login, profile, wrongStatus},
+			wantVerdict: automators.VerdictDown,
+			wantSteps:   []automators.Verdict{automators.VerdictUp, automators.VerdictUp, automators.VerdictDown},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			user := tt.user
+			if user == "" {
+				user = "ops"
+			}
+
+			got, err := automators.NewScenarioChecker().Execute(ctx, &automators.JobConfig{
+				Type: automators.CheckTypeScenario,
+				Task: automators.Task{Scenario: &automators.ScenarioTask{
+					Variables: map[string]string{"base": server.URL, "user": user},
+					Steps:     tt.steps,
+				}},
+			})
+			if err != nil {
+				t.Fatalf("scenarioChecker.Execute() error = %v", err)
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("scenarioChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if len(got.Scenario.Steps) != len(tt.wantSteps) {
+				t.Fatalf("scenarioChecker.Execute() ran %d steps, want %d", len(got.Scenario.Steps), len(tt.wantSteps))
+			}
+			for i, step := range got.Scenario.Steps {
+				if step.Verdict != tt.wantSteps[i] {
+					t.Errorf("scenarioChecker.Execute() step %q verdict = %v, want %v (%s)", step.Name, step.Verdict, tt.wantSteps[i], step.Error)
+				}
+				if step.Duration <= 0 {
+					t.Errorf("scenarioChecker.Execute() step %q has no timing", step.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestScenarioChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			task: automators.Task{Scenario: &automators.ScenarioTask{Steps: []automators.ScenarioStep{
+				{URL: "https://api.internal/login", Extract: []automators.Extraction{{Variable: "token", Source: automators.ExtractJSON, Path: "token"}}},
+				{URL: "https://api.internal/me", Headers: map[string]string{"Authorization": "Bearer {{token}}"}},
+			}}},
+			wantErr: false,
+		},
+		{
+			name:    "no steps",
+			task:    automators.Task{Scenario: &automators.ScenarioTask{}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid scheme",
+			task:    automators.Task{Scenario: &automators.ScenarioTask{Steps: []automators.ScenarioStep{{URL: "ftp://api.internal"}}}},
+			wantErr: true,
+		},
+		{
+			name: "unknown extraction source",
+			task: automators.Task{Scenario: &automators.ScenarioTask{Steps: []automators.ScenarioStep{
+				{URL: "https://api.internal", Extract: []automators.Extraction{{Variable: "token", Source: "xpath", Path: "//token"}}},
+			}}},
+			wantErr: true,
+		},
+		{
+			name: "auth options",
+			task: automators.Task{
+				AuthHeader: automators.AuthHeader{Scheme: "Basic", Username: "ops", Password: "hunter2"},
+				Scenario:   &automators.ScenarioTask{Steps: []automators.ScenarioStep{{URL: "https://api.internal"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "redirect policy",
+			task: automators.Task{
+				Redirect: &automators.RedirectPolicy{Mode: automators.RedirectNone},
+				Scenario: &automators.ScenarioTask{Steps: []automators.ScenarioStep{{URL: "https://api.internal"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "assertions",
+			task: automators.Task{
+				Assertions: []string{"status == 200"},
+				Scenario:   &automators.ScenarioTask{Steps: []automators.ScenarioStep{{URL: "https://api.internal"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid regex",
+			task: automators.Task{Scenario: &automators.ScenarioTask{Steps: []automators.ScenarioStep{
+				{URL: "https://api.internal", Extract: []automators.Extraction{{Variable: "id", Source: automators.ExtractRegex, Path: "id=("}}},
+			}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewScenarioChecker().Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("scenarioChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,422 @@
+package automators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime/metrics"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+)
+
+const (
+	defaultScriptTimeout     = time.Second
+	maxScriptTimeout         = 10 * time.Second
+	defaultScriptMemoryLimit = 32 << 20
+	maxScriptMemoryLimit     = 256 << 20
+	maxScriptMetrics         = 20
+	scriptMemoryPollInterval = 5 * time.Millisecond
+	heapObjectsMetric        = "/memory/classes/heap/objects:bytes"
+	// longest %f of a float64 without a precision
+	maxFormattedNumber = 320
+)
+
+var scriptMetricName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// widths and precisions of string.format directives
+var scriptFormatPadding = regexp.MustCompile(`%[-+ #0]*(\d*)(?:\.(\d*))?[a-zA-Z%]`)
+
+// base functions that reach the filesystem, load code or leak state between
+// runs are removed from the sandbox
+var unsafeScriptGlobals = []string{
+	"collectgarbage", "dofile", "getfenv", "load", "loadfile", "loadstring",
+	"module", "newproxy", "print", "_printregs", "require", "setfenv",
+}
+
+type scriptResponse struct {
+	status  int
+	headers http.Header
+	body    []byte
+	decoded any
+	latency time.Duration
+}
+
+type scriptOutcome struct {
+	verdict Verdict
+	message string
+	metrics map[string]float64
+}
+
+func validateScript(task *ScriptTask) error {
+	if task.Source == "" {
+		return fmt.Errorf("script requires source")
+	}
+
+	if task.Timeout < 0 || task.Timeout > maxScriptTimeout {
+		return fmt.Errorf("script timeout must be between 0 and %s", maxScriptTimeout)
+	}
+
+	if task.MemoryLimit < 0 || task.MemoryLimit > maxScriptMemoryLimit {
+		return fmt.Errorf("script memory limit must be between 0 and %d bytes", maxScriptMemoryLimit)
+	}
+
+	_, err := compileScript(task.Source)
+	return err
+}
+
+func compileScript(source string) (*lua.FunctionProto, error) {
+	chunk, err := parse.Parse(strings.NewReader(source), "script")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing script: %w", err)
+	}
+
+	proto, err := lua.Compile(chunk, "script")
+	if err != nil {
+		return nil, fmt.Errorf("error compiling script: %w", err)
+	}
+	return proto, nil
+}
+
+// runScript runs a job's script against the response in a fresh sandboxed
+// state. The script returns a boolean, a verdict string or a table with
+// verdict, error and metrics fields.
+func runScript(ctx context.Context, task *ScriptTask, response *scriptResponse) (*scriptOutcome, error) {
+	proto, err := compileScript(task.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := task.Timeout
+	if timeout <= 0 {
+		timeout = defaultScriptTimeout
+	}
+	memoryLimit := task.MemoryLimit
+	if memoryLimit <= 0 {
+		memoryLimit = defaultScriptMemoryLimit
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var memoryExceeded atomic.Bool
+	go watchScriptMemory(ctx, uint64(memoryLimit), func() {
+		memoryExceeded.Store(true)
+		cancel()
+	})
+
+	budget := &scriptBudget{limit: memoryLimit}
+	L := newScriptState(budget)
+	defer L.Close()
+	L.SetContext(ctx)
+
+	L.SetGlobal("response", scriptResponseTable(L, response))
+	L.Push(L.NewFunctionFromProto(proto))
+	if err := L.PCall(0, 1, nil); err != nil {
+		switch {
+		case budget.exceeded || memoryExceeded.Load():
+			return nil, fmt.Errorf("script exceeded memory limit of %d bytes", memoryLimit)
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			return nil, fmt.Errorf("script exceeded time limit of %s", timeout)
+		}
+
+		// the stack trace only points into the script's own source
+		var apiErr *lua.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, errors.New(apiErr.Object.String())
+		}
+		return nil, err
+	}
+
+	return scriptResult(L.Get(-1))
+}
+
+func newScriptState(budget *scriptBudget) *lua.LState {
+	L := lua.NewState(lua.Options{
+		SkipOpenLibs:        true,
+		CallStackSize:       200,
+		RegistrySize:        1024,
+		RegistryMaxSize:     64 * 1024,
+		MinimizeStackMemory: true,
+	})
+
+	for name, open := range map[string]lua.LGFunction{
+		lua.BaseLibName:   lua.OpenBase,
+		lua.TabLibName:    lua.OpenTable,
+		lua.StringLibName: lua.OpenString,
+		lua.MathLibName:   lua.OpenMath,
+	} {
+		L.Push(L.NewFunction(open))
+		L.Push(lua.LString(name))
+		L.Call(1, 0)
+	}
+
+	for _, name := range unsafeScriptGlobals {
+		L.SetGlobal(name, lua.LNil)
+	}
+
+	if strlib, ok := L.GetGlobal(lua.StringLibName).(*lua.LTable); ok {
+		strlib.RawSetString("dump", lua.LNil)
+		strlib.RawSetString("rep", L.NewFunction(boundedStringRep(budget)))
+		bound(L, strlib, "format", budget.chargeFormat)
+	}
+	if tablib, ok := L.GetGlobal(lua.TabLibName).(*lua.LTable); ok {
+		bound(L, tablib, "concat", budget.chargeConcat)
+	}
+	return L
+}
+
+// scriptBudget counts the bytes a script allocates through the library
+// functions that build strings, failing the script once it passes the memory
+// limit. The .. operator and table growth cannot be hooked in gopher-lua;
+// those are only caught by watchScriptMemory.
+type scriptBudget struct {
+	limit    int64
+	used     int64
+	exceeded bool
+}
+
+func (b *scriptBudget) charge(L *lua.LState, size int64) {
+	if size < 0 || size > b.limit-b.used {
+		b.exceeded = true
+		L.RaiseError("script exceeded memory limit of %d bytes", b.limit)
+		return
+	}
+	b.used += size
+}
+
+// bound wraps a library function so the size of its result is charged before
+// it is built.
+func bound(L *lua.LState, lib *lua.LTable, name string, charge func(L *lua.LState)) {
+	original, ok := lib.RawGetString(name).(*lua.LFunction)
+	if !ok || !original.IsG {
+		return
+	}
+	call := original.GFunction
+	lib.RawSetString(name, L.NewFunction(func(L *lua.LState) int {
+		charge(L)
+		return call(L)
+	}))
+}
+
+// chargeFormat charges an upper bound on the size of a string.format result:
+// the format itself, every width and precision it asks for, and each argument
+// as a string or a formatted number.
+func (b *scriptBudget) chargeFormat(L *lua.LState) {
+	format := L.CheckString(1)
+	size := int64(len(format))
+	// %q escapes a byte into at most four
+	perByte := int64(1)
+	for _, match := range scriptFormatPadding.FindAllStringSubmatch(format, -1) {
+		for _, digits := range match[1:] {
+			if n, err := strconv.ParseInt(digits, 10, 64); err == nil {
+				size += n
+			}
+		}
+		if strings.HasSuffix(match[0], "q") {
+			perByte = 4
+		}
+	}
+	for i := 2; i <= L.GetTop(); i++ {
+		if s, ok := L.Get(i).(lua.LString); ok {
+			size += perByte * int64(len(s))
+			continue
+		}
+		size += maxFormattedNumber
+	}
+	b.charge(L, size)
+}
+
+// chargeConcat charges the exact size of a table.concat result.
+func (b *scriptBudget) chargeConcat(L *lua.LState) {
+	table := L.CheckTable(1)
+	sep := int64(len(L.OptString(2, "")))
+	i := L.OptInt(3, 1)
+	j := L.OptInt(4, table.Len())
+	if i < 1 {
+		i = 1
+	}
+
+	var size int64
+	for k := i; k <= j && k <= table.Len(); k++ {
+		size += int64(len(lua.LVAsString(table.RawGetInt(k))))
+		if k != j {
+			size += sep
+		}
+	}
+	b.charge(L, size)
+}
+
+// boundedStringRep replaces string.rep, which can otherwise allocate far past
+// the memory limit in a single call.
+func boundedStringRep(budget *scriptBudget) lua.LGFunction {
+	return func(L *lua.LState) int {
+		s := L.CheckString(1)
+		n := L.CheckInt(2)
+		if n <= 0 || s == "" {
+			L.Push(lua.LString(""))
+			return 1
+		}
+		if int64(n) > budget.limit/int64(len(s)) {
+			budget.charge(L, -1)
+			return 0
+		}
+		budget.charge(L, int64(n)*int64(len(s)))
+		L.Push(lua.LString(strings.Repeat(s, n)))
+		return 1
+	}
+}
+
+// watchScriptMemory calls exceeded when the heap grows by more than limit
+// while the script runs. It is a best-effort backstop for allocations the
+// budget cannot see: heap usage is process wide, so other checks running at
+// the same time count against the limit too.
+func watchScriptMemory(ctx context.Context, limit uint64, exceeded func()) {
+	sample := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return
+	}
+	baseline := sample[0].Value.Uint64()
+
+	ticker := time.NewTicker(scriptMemoryPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			metrics.Read(sample)
+			if used := sample[0].Value.Uint64(); used > baseline && used-baseline > limit {
+				exceeded()
+				return
+			}
+		}
+	}
+}
+
+func scriptResponseTable(L *lua.LState, response *scriptResponse) *lua.LTable {
+	headers := L.NewTable()
+	for key, values := range response.headers {
+		headers.RawSetString(key, lua.LString(strings.Join(values, ", ")))
+	}
+
+	table := L.NewTable()
+	table.RawSetString("status", lua.LNumber(response.status))
+	table.RawSetString("headers", headers)
+	table.RawSetString("body", lua.LString(response.body))
+	table.RawSetString("json", toLuaValue(L, response.decoded))
+	table.RawSetString("latency", lua.LNumber(float64(response.latency)/float64(time.Millisecond)))
+	return table
+}
+
+func toLuaValue(L *lua.LState, value any) lua.LValue {
+	switch v := value.(type) {
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []any:
+		table := L.NewTable()
+		for _, item := range v {
+			table.Append(toLuaValue(L, item))
+		}
+		return table
+	case map[string]any:
+		table := L.NewTable()
+		for key, item := range v {
+			table.RawSetString(key, toLuaValue(L, item))
+		}
+		return table
+	}
+	return lua.LNil
+}
+
+func scriptResult(value lua.LValue) (*scriptOutcome, error) {
+	table, ok := value.(*lua.LTable)
+	if !ok {
+		verdict, err := scriptVerdict(value)
+		if err != nil {
+			return nil, err
+		}
+		return &scriptOutcome{verdict: verdict}, nil
+	}
+
+	verdict, err := scriptVerdict(table.RawGetString("verdict"))
+	if err != nil {
+		return nil, err
+	}
+	outcome := &scriptOutcome{verdict: verdict}
+
+	if message, ok := table.RawGetString("error").(lua.LString); ok {
+		outcome.message = string(message)
+	}
+
+	switch values := table.RawGetString("metrics").(type) {
+	case *lua.LNilType:
+	case *lua.LTable:
+		outcome.metrics, err = scriptMetrics(values)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("script metrics must be a table, got %s", values.Type())
+	}
+	return outcome, nil
+}
+
+func scriptVerdict(value lua.LValue) (Verdict, error) {
+	switch v := value.(type) {
+	case lua.LBool:
+		if v {
+			return VerdictUp, nil
+		}
+		return VerdictDown, nil
+	case lua.LString:
+		switch verdict := Verdict(v); verdict {
+		case VerdictUp, VerdictWarn, VerdictDown:
+			return verdict, nil
+		}
+		return "", fmt.Errorf("script returned unknown verdict %q", string(v))
+	}
+	return "", fmt.Errorf("script must return a verdict, got %s", value.Type())
+}
+
+func scriptMetrics(table *lua.LTable) (map[string]float64, error) {
+	values := make(map[string]float64)
+	var err error
+	table.ForEach(func(key, value lua.LValue) {
+		if err != nil {
+			return
+		}
+
+		name, ok := key.(lua.LString)
+		if !ok || !scriptMetricName.MatchString(string(name)) {
+			err = fmt.Errorf("invalid script metric name %s", key.String())
+			return
+		}
+		number, ok := value.(lua.LNumber)
+		if !ok {
+			err = fmt.Errorf("script metric %s must be a number, got %s", name, value.Type())
+			return
+		}
+		values[string(name)] = float64(number)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) > maxScriptMetrics {
+		return nil, fmt.Errorf("script returned %d metrics, at most %d are allowed", len(values), maxScriptMetrics)
+	}
+	return values, nil
+}
@@ -0,0 +1,186 @@
+package automators_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func TestHTTPChecker_Script(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"queue":12,"items":[{"id":1},{"id":2}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		name        string
+		script      automators.ScriptTask
+		wantVerdict automators.Verdict
+		wantError   string
+		wantMetrics map[string]float64
+	}{
+		{
+			name:        "boolean verdict",
+			script:      automators.ScriptTask{Source: `return response.status == 200 and response.json.items[2].id == 2 and response.headers["Content-Type"] == "application/json"`},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "table verdict with error",
+			script:      automators.ScriptTask{Source: `return {verdict = "down", error = "queue backed up"}`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "queue backed up",
+		},
+		{
+			name:        "custom metrics",
+			script:      automators.ScriptTask{Source: `return {verdict = "up", metrics = {queue_depth = response.json.queue, items = #response.json.items}}`},
+			wantVerdict: automators.VerdictUp,
+			wantMetrics: map[string]float64{"queue_depth": 12, "items": 2},
+		},
+		{
+			name:        "runtime error",
+			script:      automators.ScriptTask{Source: `error("boom")`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "boom",
+		},
+		{
+			name:        "no verdict returned",
+			script:      automators.ScriptTask{Source: `local x = 1`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "script must return a verdict",
+		},
+		{
+			name:        "sandboxed globals",
+			script:      automators.ScriptTask{Source: `return io == nil and os == nil and require == nil and dofile == nil and loadstring == nil and load == nil`},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:        "no filesystem access",
+			script:      automators.ScriptTask{Source: `return dofile("/etc/passwd")`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "attempt to call a non-function object",
+		},
+		{
+			name:        "time limit",
+			script:      automators.ScriptTask{Source: `while true do end`, Timeout: 100 * time.Millisecond},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "script exceeded time limit of 100ms",
+		},
+		{
+			name:        "memory limit",
+			script:      automators.ScriptTask{Source: `local t = {} for i = 1, 1e9 do t[i] = string.format("%064d", i) end`, Timeout: 5 * time.Second, MemoryLimit: 8 << 20},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "script exceeded memory limit of 8388608 bytes",
+		},
+		{
+			name:        "bounded table concat",
+			script:      automators.ScriptTask{Source: `local s = "x" for i = 1, 1e9 do s = table.concat({s, s}) end`, Timeout: 5 * time.Second, MemoryLimit: 1 << 20},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "script exceeded memory limit of 1048576 bytes",
+		},
+		{
+			name:        "bounded string format width",
+			script:      automators.ScriptTask{Source: `return #string.format("%99999d", 1) > 0`, MemoryLimit: 64 << 10},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "script exceeded memory limit of 65536 bytes",
+		},
+		{
+			name:        "bounded string rep",
+			script:      automators.ScriptTask{Source: `return #string.rep("x", 1e12) > 0`},
+			wantVerdict: automators.VerdictDown,
+			wantError:   "script exceeded memory limit",
+		},
+		{
+			name:        "string builders within the limit",
+			script:      automators.ScriptTask{Source: `return table.concat({string.format("%05d", 7), string.rep("x", 3)}, "-") == "00007-xxx"`},
+			wantVerdict: automators.VerdictUp,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			script := tt.script
+			task := automators.Task{URL: server.URL, Script: &script}
+			if err := automators.NewHTTPChecker().Validate(task); err != nil {
+				t.Fatalf("httpChecker.Validate() error = %v", err)
+			}
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: task})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("httpChecker.Execute() verdict = %s, want %s (error %q)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if !strings.Contains(got.Error, tt.wantError) {
+				t.Errorf("httpChecker.Execute() error = %q, want %q", got.Error, tt.wantError)
+			}
+			if got.Script == nil {
+				t.Fatal("httpChecker.Execute() recorded no script result")
+			}
+			if tt.wantMetrics != nil && !reflect.DeepEqual(got.Script.Metrics, tt.wantMetrics) {
+				t.Errorf("script metrics = %v, want %v", got.Script.Metrics, tt.wantMetrics)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_ValidateScript(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		script  automators.ScriptTask
+		wantErr bool
+	}{
+		{
+			name:   "valid script",
+			script: automators.ScriptTask{Source: `return true`, Timeout: time.Second, MemoryLimit: 1 << 20},
+		},
+		{
+			name:    "empty source",
+			script:  automators.ScriptTask{},
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			script:  automators.ScriptTask{Source: `return (`},
+			wantErr: true,
+		},
+		{
+			name:    "timeout too long",
+			script:  automators.ScriptTask{Source: `return true`, Timeout: time.Minute},
+			wantErr: true,
+		},
+		{
+			name:    "memory limit too large",
+			script:  automators.ScriptTask{Source: `return true`, MemoryLimit: 1 << 40},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			script := tt.script
+			err := automators.NewHTTPChecker().Validate(automators.Task{URL: "http://127.0.0.1/ping", Script: &script})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("httpChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package automators
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultSignatureHeader = "X-Signature"
+	defaultTimestampHeader = "X-Timestamp"
+	defaultCanonicalFormat = "{timestamp}.{body}"
+)
+
+func signingHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case "", "sha256":
+		return sha256.New, nil
+	case "sha1":
+		return sha1.New, nil
+	case "sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+}
+
+func validateSigning(config *SigningConfig) error {
+	if config.Key == "" {
+		return fmt.Errorf("request signing requires a key")
+	}
+
+	if _, err := signingHash(config.Algorithm); err != nil {
+		return err
+	}
+
+	if config.Encoding != "" && config.Encoding != "hex" && config.Encoding != "base64" {
+		return fmt.Errorf("unsupported signature encoding %q", config.Encoding)
+	}
+	return nil
+}
+
+// signRequest adds a timestamp header and an HMAC of the canonical string to
+// the request. The canonical format may reference {timestamp}, {method},
+// {host}, {path}, {query} and {body}.
+func signRequest(request *http.Request, body []byte, config *SigningConfig, now time.Time) error {
+	newHash, err := signingHash(config.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	format := config.CanonicalFormat
+	if format == "" {
+		format = defaultCanonicalFormat
+	}
+	canonical := strings.NewReplacer(
+		"{timestamp}", timestamp,
+		"{method}", request.Method,
+		"{host}", request.URL.Host,
+		"{path}", request.URL.EscapedPath(),
+		"{query}", request.URL.RawQuery,
+		"{body}", string(body),
+	).Replace(format)
+
+	mac := hmac.New(newHash, []byte(config.Key))
+	mac.Write([]byte(canonical))
+
+	signature := hex.EncodeToString(mac.Sum(nil))
+	if config.Encoding == "base64" {
+		signature = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+
+	signatureHeader := config.SignatureHeader
+	if signatureHeader == "" {
+		signatureHeader = defaultSignatureHeader
+	}
+	timestampHeader := config.TimestampHeader
+	if timestampHeader == "" {
+		timestampHeader = defaultTimestampHeader
+	}
+
+	request.Header.Set(timestampHeader, timestamp)
+	request.Header.Set(signatureHeader, config.SignaturePrefix+signature)
+	return nil
+}
@@ -0,0 +1,175 @@
+package automators_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func startSignedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	verify := func(newHash func() hash.Hash, canonical, signature string, encode func([]byte) string) bool {
+		mac := hmac.New(newHash, []byte("shared-secret"))
+		mac.Write([]byte(canonical))
+		return hmac.Equal([]byte(encode(mac.Sum(nil))), []byte(signature))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/webhook", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get("X-Timestamp")
+		if ts, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(ts, 0)) > time.Minute {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" ||
+			!verify(sha256.New, timestamp+"."+string(body), r.Header.Get("X-Signature"), hex.EncodeToString) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get("X-Hub-Time")
+		canonical := r.Method + "\n" + r.URL.Path + "\n" + timestamp + "\n" + string(body)
+		signature := r.Header.Get("X-Hub-Signature")
+		if len(signature) < 7 || signature[:7] != "sha512=" ||
+			!verify(sha512.New, canonical, signature[7:], base64.StdEncoding.EncodeToString) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPChecker_Signing(t *testing.T) {
+	t.Parallel()
+
+	server := startSignedServer(t)
+
+	tests := []struct {
+		name        string
+		task        automators.Task
+		wantVerdict automators.Verdict
+	}{
+		{
+			name: "default format",
+			task: automators.Task{
+				URL:     server.URL + "/webhook",
+				Method:  http.MethodPost,
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"event":"ping"}`,
+				Signing: &automators.SigningConfig{Key: "shared-secret"},
+			},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name: "custom headers and canonical format",
+			task: automators.Task{
+				URL:    server.URL + "/events",
+				Method: http.MethodPut,
+				Body:   "payload",
+				Signing: &automators.SigningConfig{
+					Key:             "shared-secret",
+					Algorithm:       "sha512",
+					Encoding:        "base64",
+					SignatureHeader: "X-Hub-Signature",
+					SignaturePrefix: "sha512=",
+					TimestampHeader: "X-Hub-Time",
+					CanonicalFormat: "{method}\n{path}\n{timestamp}\n{body}",
+				},
+			},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name: "wrong key",
+			task: automators.Task{
+				URL:     server.URL + "/webhook",
+				Method:  http.MethodPost,
+				Headers: map[string]string{"Content-Type": "application/json"},
+				Body:    `{"event":"ping"}`,
+				Signing: &automators.SigningConfig{Key: "rotated-secret"},
+			},
+			wantVerdict: automators.VerdictDown,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: tt.task})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("httpChecker.Execute() verdict = %v, want %v (status %d)", got.Verdict, tt.wantVerdict, got.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_ValidateSigning(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		signing automators.SigningConfig
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			signing: automators.SigningConfig{Key: "shared-secret", Algorithm: "sha1", Encoding: "hex"},
+			wantErr: false,
+		},
+		{
+			name:    "missing key",
+			signing: automators.SigningConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported algorithm",
+			signing: automators.SigningConfig{Key: "shared-secret", Algorithm: "md5"},
+			wantErr: true,
+		},
+		{
+			name:    "unsupported encoding",
+			signing: automators.SigningConfig{Key: "shared-secret", Encoding: "base32"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			signing := tt.signing
+			task := automators.Task{URL: "https://hooks.internal/webhook", Signing: &signing}
+			if err := automators.NewHTTPChecker().Validate(task); (err != nil) != tt.wantErr {
+				t.Errorf("httpChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package automators
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+const (
+	maxTCPResponseBytes = 4096
+)
+
+type tcpChecker struct{}
+
+func NewTCPChecker() Checker {
+	return &tcpChecker{}
+}
+
+func (t *tcpChecker) Validate(task Task) error {
+	if task.TCP == nil {
+		return fmt.Errorf("tcp check requires tcp settings")
+	}
+
+	host, port, err := net.SplitHostPort(task.TCP.Address)
+	if err != nil {
+		return fmt.Errorf("invalid tcp address: %w", err)
+	}
+
+	if host == "" {
+		return fmt.Errorf("tcp address has no host")
+	}
+
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("invalid tcp port %q", port)
+	}
+	return nil
+}
+
+func (t *tcpChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.TCP
+	if task == nil {
+		return nil, fmt.Errorf("tcp check requires tcp settings")
+	}
+
+	start := time.Now()
+	conn, err := dialContext(ctx, config.Task.Proxy, "tcp", task.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting: %w", err)
+	}
+	defer conn.Close()
+
+	result := &RunResult{
+		Verdict: VerdictUp,
+		TCP: &TCPResult{
+			ConnectLatency: time.Since(start),
+		},
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return nil, fmt.Errorf("error setting deadline: %w", err)
+		}
+	}
+
+	if task.Payload != "" {
+		if _, err := conn.Write([]byte(task.Payload)); err != nil {
+			return nil, fmt.Errorf("error sending payload: %w", err)
+		}
+	}
+
+	if task.Expect == "" {
+		return result, nil
+	}
+
+	response, err := readUntil(conn, []byte(task.Expect))
+	result.TCP.Response = string(response)
+	if err != nil {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("expected response %q not received: %s", task.Expect, err)
+	}
+	return result, nil
+}
+
+func readUntil(conn net.Conn, expect []byte) ([]byte, error) {
+	response := make([]byte, 0, 512)
+	buf := make([]byte, 512)
+
+	for len(response) < maxTCPResponseBytes {
+		n, err := conn.Read(buf)
+		response = append(response, buf[:n]...)
+		if bytes.Contains(response, expect) {
+			return response, nil
+		}
+		if err != nil {
+			return response, err
+		}
+	}
+	return response, fmt.Errorf("response exceeded %d bytes", maxTCPResponseBytes)
+}
@@ -0,0 +1,176 @@
+package automators_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func startTCPServer(t *testing.T, handle func(net.Conn)) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestTCPChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid address",
+			task:    automators.Task{TCP: &automators.TCPTask{Address: "db.internal:5432"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing settings",
+			task:    automators.Task{},
+			wantErr: true,
+		},
+		{
+			name:    "missing port",
+			task:    automators.Task{TCP: &automators.TCPTask{Address: "db.internal"}},
+			wantErr: true,
+		},
+		{
+			name:    "port out of range",
+			task:    automators.Task{TCP: &automators.TCPTask{Address: "db.internal:70000"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewTCPChecker().Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("tcpChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTCPChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	bannerAddr := startTCPServer(t, func(conn net.Conn) {
+		conn.Write([]byte("220 smtp.test ESMTP ready\r\n"))
+	})
+	echoAddr := startTCPServer(t, func(conn net.Conn) {
+		line, err := bufio.NewReader(conn).ReadString('\n')
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("+" + line))
+	})
+	silentAddr := startTCPServer(t, func(conn net.Conn) {
+		time.Sleep(time.Second)
+	})
+
+	closedListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closedListener.Addr().String()
+	closedListener.Close()
+
+	tests := []struct {
+		name         string
+		task         *automators.TCPTask
+		wantVerdict  automators.Verdict
+		wantResponse string
+		wantErr      bool
+	}{
+		{
+			name:        "connect only",
+			task:        &automators.TCPTask{Address: bannerAddr},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:         "banner match",
+			task:         &automators.TCPTask{Address: bannerAddr, Expect: "ESMTP"},
+			wantVerdict:  automators.VerdictUp,
+			wantResponse: "220 smtp.test ESMTP ready\r\n",
+		},
+		{
+			name:         "payload and response match",
+			task:         &automators.TCPTask{Address: echoAddr, Payload: "PING\r\n", Expect: "+PING"},
+			wantVerdict:  automators.VerdictUp,
+			wantResponse: "+PING\r\n",
+		},
+		{
+			name:         "banner mismatch",
+			task:         &automators.TCPTask{Address: bannerAddr, Expect: "IMAP"},
+			wantVerdict:  automators.VerdictDown,
+			wantResponse: "220 smtp.test ESMTP ready\r\n",
+		},
+		{
+			name:        "no response before timeout",
+			task:        &automators.TCPTask{Address: silentAddr, Expect: "hello"},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:    "connection refused",
+			task:    &automators.TCPTask{Address: closedAddr},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			got, err := automators.NewTCPChecker().Execute(ctx, &automators.JobConfig{
+				Type: automators.CheckTypeTCP,
+				Task: automators.Task{TCP: tt.task},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tcpChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("tcpChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if got.TCP == nil || got.TCP.ConnectLatency <= 0 {
+				t.Errorf("tcpChecker.Execute() did not report connect latency: %+v", got.TCP)
+				return
+			}
+			if got.TCP.Response != tt.wantResponse {
+				t.Errorf("tcpChecker.Execute() response = %q, want %q", got.TCP.Response, tt.wantResponse)
+			}
+		})
+	}
+}
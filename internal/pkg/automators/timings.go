@@ -0,0 +1,82 @@
+package automators
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// httpTimer records connection phase timestamps from an httptrace.ClientTrace.
+type httpTimer struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+	reused       bool
+}
+
+func newHTTPTimer() *httpTimer {
+	return &httpTimer{start: time.Now()}
+}
+
+func (t *httpTimer) trace() *httptrace.ClientTrace {
+	record := func(field *time.Time) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		*field = time.Now()
+	}
+
+	return &httptrace.ClientTrace{
+		GetConn: func(string) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			// a digest retry starts a second request on the same trace
+			t.start = time.Now()
+			t.dnsStart, t.dnsDone = time.Time{}, time.Time{}
+			t.connectStart, t.connectDone = time.Time{}, time.Time{}
+			t.tlsStart, t.tlsDone = time.Time{}, time.Time{}
+			t.firstByte = time.Time{}
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			defer t.mu.Unlock()
+			t.reused = info.Reused
+		},
+		DNSStart:          func(httptrace.DNSStartInfo) { record(&t.dnsStart) },
+		DNSDone:           func(httptrace.DNSDoneInfo) { record(&t.dnsDone) },
+		ConnectStart:      func(string, string) { record(&t.connectStart) },
+		ConnectDone:       func(string, string, error) { record(&t.connectDone) },
+		TLSHandshakeStart: func() { record(&t.tlsStart) },
+		TLSHandshakeDone:  func(tls.ConnectionState, error) { record(&t.tlsDone) },
+		GotFirstResponseByte: func() {
+			record(&t.firstByte)
+		},
+	}
+}
+
+func (t *httpTimer) timings(bodyDone time.Time) *HTTPTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := func(from, to time.Time) time.Duration {
+		if from.IsZero() || to.IsZero() || to.Before(from) {
+			return 0
+		}
+		return to.Sub(from)
+	}
+
+	return &HTTPTimings{
+		DNSLookup:        span(t.dnsStart, t.dnsDone),
+		TCPConnect:       span(t.connectStart, t.connectDone),
+		TLSHandshake:     span(t.tlsStart, t.tlsDone),
+		TimeToFirstByte:  span(t.start, t.firstByte),
+		Transfer:         span(t.firstByte, bodyDone),
+		ConnectionReused: t.reused,
+	}
+}
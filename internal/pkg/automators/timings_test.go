@@ -0,0 +1,196 @@
+package automators_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/mock"
+)
+
+const (
+	serverDelay   = 50 * time.Millisecond
+	transferDelay = 30 * time.Millisecond
+)
+
+func slowHandler(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(serverDelay)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(`{"status":`))
+	w.(http.Flusher).Flush()
+	time.Sleep(transferDelay)
+	w.Write([]byte(`"ok"}`))
+}
+
+func TestHTTPChecker_Timings(t *testing.T) {
+	t.Parallel()
+
+	plain := httptest.NewServer(http.HandlerFunc(slowHandler))
+	t.Cleanup(plain.Close)
+	secure := httptest.NewTLSServer(http.HandlerFunc(slowHandler))
+	t.Cleanup(secure.Close)
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantTLS bool
+	}{
+		{
+			name:    "plain http",
+			task:    automators.Task{URL: plain.URL, FreshConnection: true},
+			wantTLS: false,
+		},
+		{
+			name:    "https",
+			task:    automators.Task{URL: secure.URL, FreshConnection: true, TLS: &automators.TLSOptions{InsecureSkipVerify: true}},
+			wantTLS: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{Task: tt.task})
+			if err != nil {
+				t.Fatalf("httpChecker.Execute() error = %v", err)
+			}
+
+			timings := got.Timings
+			if timings == nil {
+				t.Fatal("httpChecker.Execute() reported no timings")
+			}
+			if timings.TCPConnect <= 0 {
+				t.Errorf("tcp connect = %s, want > 0", timings.TCPConnect)
+			}
+			if (timings.TLSHandshake > 0) != tt.wantTLS {
+				t.Errorf("tls handshake = %s, want tls %v", timings.TLSHandshake, tt.wantTLS)
+			}
+			if timings.TimeToFirstByte < serverDelay {
+				t.Errorf("time to first byte = %s, want >= %s", timings.TimeToFirstByte, serverDelay)
+			}
+			if timings.Transfer < transferDelay {
+				t.Errorf("transfer = %s, want >= %s", timings.Transfer, transferDelay)
+			}
+			if timings.ConnectionReused {
+				t.Error("fresh connection reported as reused")
+			}
+		})
+	}
+}
+
+func TestAutomator_ExportsCheckMetrics(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	registry := prometheus.NewRegistry()
+	store := &mock.CacherStore{
+		Cache:    make(map[string]string),
+		CacheSet: make(map[string]struct{}),
+		SetName:  "jobs_set",
+	}
+	a := automators.NewAutomator(store, []byte("kHQXeA!12mR56<OVDC0G7ZNEi(WiecmZ"), gocron.NewScheduler(time.Local), zap.NewExample().Sugar(),
+		automators.WithMetricsRegisterer(registry),
+	)
+
+	ctx := context.Background()
+	if err := a.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Stop(ctx)
+
+	jobID, err := a.CreateNewJob(ctx, automators.JobConfig{
+		CronExpression: "* * * * * *",
+		Task:           automators.Task{URL: server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]bool{
+		"ping_check_runs_total":                  false,
+		"ping_check_duration_seconds":            false,
+		"ping_check_http_phase_duration_seconds": false,
+		"ping_check_up":                          false,
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		families, err := registry.Gather()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, family := range families {
+			if _, ok := want[family.GetName()]; ok {
+				want[family.GetName()] = true
+			}
+		}
+
+		done := true
+		for _, found := range want {
+			done = done && found
+		}
+		if done {
+			break
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	for name, found := range want {
+		if !found {
+			t.Fatalf("metric %s not exported after a run", name)
+		}
+	}
+
+	// the server is plain http, so no tls handshake is observed
+	if phases := gatherLabels(t, registry, "ping_check_http_phase_duration_seconds", "phase"); phases["tls"] {
+		t.Errorf("tls phase observed for a plain http check: %v", phases)
+	}
+
+	jobUID, err := uuid.Parse(jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.DeleteJob(ctx, jobUID); err != nil {
+		t.Fatal(err)
+	}
+	if jobs := gatherLabels(t, registry, "ping_check_up", "job_uid"); jobs[jobID] {
+		t.Errorf("ping_check_up still exported for deleted job %s", jobID)
+	}
+}
+
+func gatherLabels(t *testing.T, registry *prometheus.Registry, family, label string) map[string]bool {
+	t.Helper()
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := make(map[string]bool)
+	for _, f := range families {
+		if f.GetName() != family {
+			continue
+		}
+		for _, metric := range f.GetMetric() {
+			for _, pair := range metric.GetLabel() {
+				if pair.GetName() == label {
+					values[pair.GetValue()] = true
+				}
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,170 @@
+package automators
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"math"
+	"net"
+	"sort"
+	"time"
+)
+
+var defaultWarningDays = []int{30, 14, 7}
+
+type tlsChecker struct {
+	rootCAs *x509.CertPool
+}
+
+// NewTLSChecker returns a checker that verifies certificate chains against
+// rootCAs, or the system roots when rootCAs is nil.
+func NewTLSChecker(rootCAs *x509.CertPool) Checker {
+	return &tlsChecker{rootCAs: rootCAs}
+}
+
+func (c *tlsChecker) Validate(task Task) error {
+	if task.Certificate == nil {
+		return fmt.Errorf("tls check requires certificate settings")
+	}
+
+	if _, _, err := net.SplitHostPort(task.Certificate.Address); err != nil {
+		return fmt.Errorf("invalid tls address: %w", err)
+	}
+	return validateCertificateTask(task.Certificate)
+}
+
+func (c *tlsChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.Certificate
+	if task == nil {
+		return nil, fmt.Errorf("tls check requires certificate settings")
+	}
+
+	host, _, err := net.SplitHostPort(task.Address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tls address: %w", err)
+	}
+
+	serverName := task.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+
+	rawConn, err := dialContext(ctx, config.Task.Proxy, "tcp", task.Address)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting: %w", err)
+	}
+	defer rawConn.Close()
+
+	// verification is done below so the chain can be reported even when it
+	// does not verify
+	conn := tls.Client(rawConn, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+	})
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return nil, fmt.Errorf("error during tls handshake: %w", err)
+	}
+
+	certificates := conn.ConnectionState().PeerCertificates
+	verifyErr := verifyCertificateChain(certificates, serverName, c.rootCAs)
+
+	result := &RunResult{Verdict: VerdictUp}
+	evaluateCertificates(result, certificates, verifyErr, task, time.Now())
+	return result, nil
+}
+
+func validateCertificateTask(task *CertificateTask) error {
+	for _, days := range task.WarningDays {
+		if days < 0 {
+			return fmt.Errorf("warning days must not be negative")
+		}
+	}
+
+	if task.FailureDays < 0 {
+		return fmt.Errorf("failure days must not be negative")
+	}
+	return nil
+}
+
+func verifyCertificateChain(certificates []*x509.Certificate, serverName string, rootCAs *x509.CertPool) error {
+	if len(certificates) == 0 {
+		return fmt.Errorf("no peer certificates presented")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	_, err := certificates[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         rootCAs,
+		Intermediates: intermediates,
+	})
+	return err
+}
+
+// evaluateCertificates records the peer chain on the result and downgrades its
+// verdict when the chain did not verify or the leaf is close to expiry.
+func evaluateCertificates(result *RunResult, certificates []*x509.Certificate, verifyErr error, task *CertificateTask, now time.Time) {
+	if len(certificates) == 0 {
+		result.Verdict = VerdictDown
+		result.Error = "no peer certificates presented"
+		return
+	}
+
+	leaf := certificates[0]
+	daysRemaining := int(math.Floor(leaf.NotAfter.Sub(now).Hours() / 24))
+
+	tlsResult := &TLSResult{
+		Subject:       leaf.Subject.String(),
+		Issuer:        leaf.Issuer.String(),
+		NotAfter:      leaf.NotAfter,
+		DaysRemaining: daysRemaining,
+		Chain:         make([]CertificateInfo, 0, len(certificates)),
+	}
+	for _, cert := range certificates {
+		tlsResult.Chain = append(tlsResult.Chain, CertificateInfo{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			NotAfter: cert.NotAfter,
+		})
+	}
+	result.TLS = tlsResult
+
+	switch {
+	case verifyErr != nil:
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("certificate verification failed: %s", verifyErr)
+		return
+
+	case now.After(leaf.NotAfter):
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("certificate expired on %s", leaf.NotAfter.Format(time.RFC3339))
+		return
+
+	case daysRemaining < task.FailureDays:
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("certificate expires in %d days, fewer than %d", daysRemaining, task.FailureDays)
+		return
+	}
+
+	warningDays := task.WarningDays
+	if len(warningDays) == 0 {
+		warningDays = defaultWarningDays
+	}
+	sortedDays := append([]int(nil), warningDays...)
+	sort.Ints(sortedDays)
+
+	for _, threshold := range sortedDays {
+		if daysRemaining <= threshold {
+			tlsResult.WarningThreshold = threshold
+			if result.Verdict == VerdictUp {
+				result.Verdict = VerdictWarn
+				result.Error = fmt.Sprintf("certificate expires in %d days", daysRemaining)
+			}
+			return
+		}
+	}
+}
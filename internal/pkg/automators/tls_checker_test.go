@@ -0,0 +1,276 @@
+package automators_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ping-app test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+func (ca *testCA) issue(t *testing.T, notAfter time.Time, extKeyUsage x509.ExtKeyUsage) tls.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "service.test"},
+		DNSNames:     []string{"service.test", "localhost"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-48 * time.Hour),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{
+		Certificate: [][]byte{der},
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}
+}
+
+func startTLSServer(t *testing.T, cert tls.Certificate) string {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: []tls.Certificate{cert}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func TestTLSChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name          string
+		notAfter      time.Time
+		task          automators.CertificateTask
+		rootCAs       *x509.CertPool
+		wantVerdict   automators.Verdict
+		wantThreshold int
+	}{
+		{
+			name:        "valid certificate",
+			notAfter:    time.Now().Add(90 * day),
+			task:        automators.CertificateTask{ServerName: "service.test"},
+			rootCAs:     ca.pool,
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:          "within 30 day warning",
+			notAfter:      time.Now().Add(20*day + time.Hour),
+			task:          automators.CertificateTask{ServerName: "service.test"},
+			rootCAs:       ca.pool,
+			wantVerdict:   automators.VerdictWarn,
+			wantThreshold: 30,
+		},
+		{
+			name:          "within 7 day warning",
+			notAfter:      time.Now().Add(5*day + time.Hour),
+			task:          automators.CertificateTask{ServerName: "service.test"},
+			rootCAs:       ca.pool,
+			wantVerdict:   automators.VerdictWarn,
+			wantThreshold: 7,
+		},
+		{
+			name:          "custom warning threshold",
+			notAfter:      time.Now().Add(50*day + time.Hour),
+			task:          automators.CertificateTask{ServerName: "service.test", WarningDays: []int{60}},
+			rootCAs:       ca.pool,
+			wantVerdict:   automators.VerdictWarn,
+			wantThreshold: 60,
+		},
+		{
+			name:        "failure threshold",
+			notAfter:    time.Now().Add(5*day + time.Hour),
+			task:        automators.CertificateTask{ServerName: "service.test", FailureDays: 10},
+			rootCAs:     ca.pool,
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "expired certificate",
+			notAfter:    time.Now().Add(-day),
+			task:        automators.CertificateTask{ServerName: "service.test"},
+			rootCAs:     ca.pool,
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "hostname mismatch",
+			notAfter:    time.Now().Add(90 * day),
+			task:        automators.CertificateTask{ServerName: "other.test"},
+			rootCAs:     ca.pool,
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:        "untrusted chain",
+			notAfter:    time.Now().Add(90 * day),
+			task:        automators.CertificateTask{ServerName: "service.test"},
+			rootCAs:     x509.NewCertPool(),
+			wantVerdict: automators.VerdictDown,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cert := ca.issue(t, tt.notAfter, x509.ExtKeyUsageServerAuth)
+			task := tt.task
+			task.Address = startTLSServer(t, cert)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			got, err := automators.NewTLSChecker(tt.rootCAs).Execute(ctx, &automators.JobConfig{
+				Type: automators.CheckTypeTLS,
+				Task: automators.Task{Certificate: &task},
+			})
+			if err != nil {
+				t.Fatalf("tlsChecker.Execute() error = %v", err)
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("tlsChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if got.TLS == nil {
+				t.Fatal("tlsChecker.Execute() did not record certificate details")
+			}
+			if got.TLS.WarningThreshold != tt.wantThreshold {
+				t.Errorf("tlsChecker.Execute() warning threshold = %v, want %v", got.TLS.WarningThreshold, tt.wantThreshold)
+			}
+			if got.TLS.Subject != "CN=service.test" || got.TLS.Issuer != "CN=ping-app test CA" {
+				t.Errorf("tlsChecker.Execute() subject = %q, issuer = %q", got.TLS.Subject, got.TLS.Issuer)
+			}
+			if !got.TLS.NotAfter.Equal(cert.Leaf.NotAfter) {
+				t.Errorf("tlsChecker.Execute() not after = %v, want %v", got.TLS.NotAfter, cert.Leaf.NotAfter)
+			}
+		})
+	}
+}
+
+func TestTLSChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			task:    automators.Task{Certificate: &automators.CertificateTask{Address: "service.test:443"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing settings",
+			task:    automators.Task{},
+			wantErr: true,
+		},
+		{
+			name:    "missing port",
+			task:    automators.Task{Certificate: &automators.CertificateTask{Address: "service.test"}},
+			wantErr: true,
+		},
+		{
+			name:    "negative warning days",
+			task:    automators.Task{Certificate: &automators.CertificateTask{Address: "service.test:443", WarningDays: []int{-1}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewTLSChecker(nil).Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("tlsChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
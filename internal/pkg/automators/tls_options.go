@@ -0,0 +1,55 @@
+package automators
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+func validateTLSOptions(options *TLSOptions) error {
+	_, err := buildTLSConfig(options)
+	return err
+}
+
+func buildTLSConfig(options *TLSOptions) (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         options.ServerName,
+		InsecureSkipVerify: options.InsecureSkipVerify,
+	}
+
+	if options.MinVersion != "" {
+		version, ok := tlsVersions[options.MinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported minimum tls version %q", options.MinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	if (options.ClientCertificate == "") != (options.ClientKey == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+
+	if options.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(options.ClientCertificate), []byte(options.ClientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	if options.CABundle != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(options.CABundle)) {
+			return nil, fmt.Errorf("ca bundle contains no certificates")
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
@@ -0,0 +1,185 @@
+package automators_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func encodePEM(t *testing.T, cert tls.Certificate) (string, string) {
+	t.Helper()
+
+	key, err := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: key})
+	return string(certPEM), string(keyPEM)
+}
+
+func startMutualTLSServer(t *testing.T, ca *testCA, maxVersion uint16) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{ca.issue(t, time.Now().Add(90*24*time.Hour), x509.ExtKeyUsageServerAuth)},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    ca.pool,
+		MaxVersion:   maxVersion,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPChecker_MutualTLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	server := startMutualTLSServer(t, ca, 0)
+	legacyServer := startMutualTLSServer(t, ca, tls.VersionTLS12)
+
+	clientCert, clientKey := encodePEM(t, ca.issue(t, time.Now().Add(90*24*time.Hour), x509.ExtKeyUsageClientAuth))
+	caBundle := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+
+	tests := []struct {
+		name    string
+		url     string
+		options automators.TLSOptions
+		wantErr bool
+	}{
+		{
+			name:    "client certificate with ca bundle",
+			url:     server.URL,
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: clientKey, CABundle: caBundle, ServerName: "service.test"},
+			wantErr: false,
+		},
+		{
+			name:    "client certificate with insecure skip verify",
+			url:     server.URL,
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: clientKey, InsecureSkipVerify: true},
+			wantErr: false,
+		},
+		{
+			name:    "missing client certificate",
+			url:     server.URL,
+			options: automators.TLSOptions{CABundle: caBundle},
+			wantErr: true,
+		},
+		{
+			name:    "untrusted server",
+			url:     server.URL,
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: clientKey},
+			wantErr: true,
+		},
+		{
+			name:    "sni mismatch",
+			url:     server.URL,
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: clientKey, CABundle: caBundle, ServerName: "other.test"},
+			wantErr: true,
+		},
+		{
+			name:    "minimum version not supported by server",
+			url:     legacyServer.URL,
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: clientKey, CABundle: caBundle, MinVersion: "1.3"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			options := tt.options
+			got, err := automators.NewHTTPChecker().Execute(ctx, &automators.JobConfig{
+				Task: automators.Task{URL: tt.url, TLS: &options},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("httpChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.Verdict != automators.VerdictUp {
+				t.Errorf("httpChecker.Execute() verdict = %v, want up (status %d)", got.Verdict, got.StatusCode)
+			}
+		})
+	}
+}
+
+func TestHTTPChecker_ValidateTLSOptions(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	clientCert, clientKey := encodePEM(t, ca.issue(t, time.Now().Add(90*24*time.Hour), x509.ExtKeyUsageClientAuth))
+
+	tests := []struct {
+		name    string
+		url     string
+		options automators.TLSOptions
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			url:     "https://api.internal",
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: clientKey, MinVersion: "1.2"},
+			wantErr: false,
+		},
+		{
+			name:    "plain http",
+			url:     "http://api.internal",
+			options: automators.TLSOptions{InsecureSkipVerify: true},
+			wantErr: true,
+		},
+		{
+			name:    "certificate without key",
+			url:     "https://api.internal",
+			options: automators.TLSOptions{ClientCertificate: clientCert},
+			wantErr: true,
+		},
+		{
+			name:    "mismatched key",
+			url:     "https://api.internal",
+			options: automators.TLSOptions{ClientCertificate: clientCert, ClientKey: "not a key"},
+			wantErr: true,
+		},
+		{
+			name:    "empty ca bundle",
+			url:     "https://api.internal",
+			options: automators.TLSOptions{CABundle: "garbage"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown tls version",
+			url:     "https://api.internal",
+			options: automators.TLSOptions{MinVersion: "1.4"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			options := tt.options
+			if err := automators.NewHTTPChecker().Validate(automators.Task{URL: tt.url, TLS: &options}); (err != nil) != tt.wantErr {
+				t.Errorf("httpChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
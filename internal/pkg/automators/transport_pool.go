@@ -0,0 +1,163 @@
+package automators
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+type TransportSettings struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// TransportPool shares HTTP transports between checks so connections to the
+// same target are reused across runs. Checks with different TLS or proxy
+// settings get separate transports, which are closed once no scheduled job
+// uses them.
+type TransportPool struct {
+	mu         sync.Mutex
+	settings   TransportSettings
+	transports map[string]*http.Transport
+	// job uid to the key of the transport it last used
+	jobs map[string]string
+	refs map[string]int
+}
+
+func NewTransportPool(settings TransportSettings) *TransportPool {
+	return &TransportPool{
+		settings:   settings,
+		transports: make(map[string]*http.Transport),
+		jobs:       make(map[string]string),
+		refs:       make(map[string]int),
+	}
+}
+
+// Client returns a client for the task. Tasks that ask for a fresh connection
+// get a one-off transport with keep-alives disabled, which the caller must
+// release with the returned func.
+func (p *TransportPool) Client(jobUID string, task *Task) (*http.Client, func(), error) {
+	if task.FreshConnection {
+		transport, err := p.newTransport(task)
+		if err != nil {
+			return nil, nil, err
+		}
+		transport.DisableKeepAlives = true
+		return &http.Client{Transport: transport}, transport.CloseIdleConnections, nil
+	}
+
+	key, err := transportKey(task)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	transport, ok := p.transports[key]
+	if !ok {
+		transport, err = p.newTransport(task)
+		if err != nil {
+			return nil, nil, err
+		}
+		p.transports[key] = transport
+	}
+
+	// a job whose settings changed moves to the new transport
+	if previous, ok := p.jobs[jobUID]; !ok || previous != key {
+		if ok {
+			p.release(previous)
+		}
+		p.jobs[jobUID] = key
+		p.refs[key]++
+	}
+	return &http.Client{Transport: transport}, func() {}, nil
+}
+
+// Release drops the job's hold on its transport, closing the transport when
+// no other job uses it.
+func (p *TransportPool) Release(jobUID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok := p.jobs[jobUID]
+	if !ok {
+		return
+	}
+	delete(p.jobs, jobUID)
+	p.release(key)
+}
+
+func (p *TransportPool) release(key string) {
+	p.refs[key]--
+	if p.refs[key] > 0 {
+		return
+	}
+	delete(p.refs, key)
+	if transport, ok := p.transports[key]; ok {
+		transport.CloseIdleConnections()
+		delete(p.transports, key)
+	}
+}
+
+func (p *TransportPool) CloseIdleConnections() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, transport := range p.transports {
+		transport.CloseIdleConnections()
+	}
+}
+
+func (p *TransportPool) newTransport(task *Task) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if p.settings.MaxIdleConns > 0 {
+		transport.MaxIdleConns = p.settings.MaxIdleConns
+	}
+	if p.settings.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = p.settings.MaxIdleConnsPerHost
+	}
+	if p.settings.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = p.settings.IdleConnTimeout
+	}
+
+	if task.Proxy != nil {
+		proxyURL, err := parseProxyURL(task.Proxy)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = nil
+		if proxyURL != nil {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+
+	if task.TLS != nil {
+		tlsConfig, err := buildTLSConfig(task.TLS)
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	return transport, nil
+}
+
+func transportKey(task *Task) (string, error) {
+	if task.TLS == nil && task.Proxy == nil {
+		return "", nil
+	}
+
+	bytes, err := json.Marshal(struct {
+		TLS   *TLSOptions
+		Proxy *ProxyConfig
+	}{task.TLS, task.Proxy})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bytes)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,170 @@
+package automators_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+func TestHTTPChecker_ConnectionReuse(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		freshConnection bool
+		runs            int
+		wantConnections int32
+	}{
+		{
+			name:            "reuses pooled connection",
+			freshConnection: false,
+			runs:            3,
+			wantConnections: 1,
+		},
+		{
+			name:            "fresh connection per run",
+			freshConnection: true,
+			runs:            3,
+			wantConnections: 3,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var connections int32
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+				if state == http.StateNew {
+					atomic.AddInt32(&connections, 1)
+				}
+			}
+			server.Start()
+			t.Cleanup(server.Close)
+
+			checker := automators.NewHTTPCheckerWithTransports(automators.NewTransportPool(automators.TransportSettings{}))
+			config := &automators.JobConfig{Task: automators.Task{URL: server.URL, FreshConnection: tt.freshConnection}}
+
+			for i := 0; i < tt.runs; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+				got, err := checker.Execute(ctx, config)
+				cancel()
+				if err != nil {
+					t.Fatalf("httpChecker.Execute() error = %v", err)
+				}
+				if got.Verdict != automators.VerdictUp {
+					t.Fatalf("httpChecker.Execute() verdict = %v, want up", got.Verdict)
+				}
+			}
+
+			if got := atomic.LoadInt32(&connections); got != tt.wantConnections {
+				t.Errorf("server saw %d connections, want %d", got, tt.wantConnections)
+			}
+		})
+	}
+}
+
+func TestTransportPool_Client(t *testing.T) {
+	t.Parallel()
+
+	pool := automators.NewTransportPool(automators.TransportSettings{
+		MaxIdleConns:        10,
+		MaxIdleConnsPerHost: 2,
+		IdleConnTimeout:     15 * time.Second,
+	})
+
+	transport := func(jobUID string, task automators.Task) *http.Transport {
+		t.Helper()
+
+		client, release, err := pool.Client(jobUID, &task)
+		if err != nil {
+			t.Fatalf("TransportPool.Client() error = %v", err)
+		}
+		defer release()
+		return client.Transport.(*http.Transport)
+	}
+
+	plain := transport("a", automators.Task{URL: "https://a.internal"})
+	if plain != transport("b", automators.Task{URL: "https://b.internal"}) {
+		t.Error("TransportPool.Client() returned different transports for identical settings")
+	}
+
+	withTLS := transport("c", automators.Task{URL: "https://a.internal", TLS: &automators.TLSOptions{MinVersion: "1.3"}})
+	if withTLS == plain {
+		t.Error("TransportPool.Client() shared a transport between different tls settings")
+	}
+	if withTLS != transport("d", automators.Task{URL: "https://c.internal", TLS: &automators.TLSOptions{MinVersion: "1.3"}}) {
+		t.Error("TransportPool.Client() returned different transports for identical tls settings")
+	}
+
+	fresh := transport("e", automators.Task{URL: "https://a.internal", FreshConnection: true})
+	if fresh == plain || !fresh.DisableKeepAlives {
+		t.Error("TransportPool.Client() did not return a one-off transport for a fresh connection")
+	}
+
+	if plain.MaxIdleConns != 10 || plain.MaxIdleConnsPerHost != 2 || plain.IdleConnTimeout != 15*time.Second {
+		t.Errorf("TransportPool.Client() idle limits = %d/%d/%s, want 10/2/15s", plain.MaxIdleConns, plain.MaxIdleConnsPerHost, plain.IdleConnTimeout)
+	}
+
+	pool.Release("c")
+	if withTLS != transport("d", automators.Task{URL: "https://c.internal", TLS: &automators.TLSOptions{MinVersion: "1.3"}}) {
+		t.Error("TransportPool.Release() closed a transport another job still uses")
+	}
+	pool.Release("d")
+	if withTLS == transport("f", automators.Task{URL: "https://c.internal", TLS: &automators.TLSOptions{MinVersion: "1.3"}}) {
+		t.Error("TransportPool.Release() kept a transport no job uses")
+	}
+
+	// a job whose settings change releases its old transport
+	transport("a", automators.Task{URL: "https://a.internal", TLS: &automators.TLSOptions{MinVersion: "1.2"}})
+	transport("b", automators.Task{URL: "https://b.internal", TLS: &automators.TLSOptions{MinVersion: "1.2"}})
+	if plain == transport("g", automators.Task{URL: "https://a.internal"}) {
+		t.Error("TransportPool.Client() kept a transport after its jobs moved to new settings")
+	}
+}
+
+func TestTransportPool_ReleaseBeforeRun(t *testing.T) {
+	t.Parallel()
+
+	pool := automators.NewTransportPool(automators.TransportSettings{})
+	task := automators.Task{URL: "https://a.internal", TLS: &automators.TLSOptions{MinVersion: "1.3"}}
+
+	client, release, err := pool.Client("a", &task)
+	if err != nil {
+		t.Fatalf("TransportPool.Client() error = %v", err)
+	}
+	release()
+	scheduled := client.Transport
+
+	// the job is unscheduled before a run already in flight takes its client
+	pool.Release("a")
+	client, release, err = pool.Client("a", &task)
+	if err != nil {
+		t.Fatalf("TransportPool.Client() error = %v", err)
+	}
+	release()
+	late := client.Transport
+	if late == scheduled {
+		t.Error("TransportPool.Client() handed out a transport that was already closed")
+	}
+	// the automator releases the job again once the run is over
+	pool.Release("a")
+
+	client, release, err = pool.Client("b", &task)
+	if err != nil {
+		t.Fatalf("TransportPool.Client() error = %v", err)
+	}
+	defer release()
+	if client.Transport == late {
+		t.Error("TransportPool.Release() kept the transport of a run that outlived its job")
+	}
+}
@@ -1,25 +1,311 @@
 package automators
 
 import (
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/expr"
 )
 
 type JobConfig struct {
 	CronExpression string    `json:"cron_expression,omitempty"`
 	UID            uuid.UUID `json:"uid,omitempty"`
+	Type           string    `json:"type,omitempty"`
 	Task           Task      `json:"task,omitempty"`
+
+	// compiled Task.Assertions, set when the job is scheduled
+	assertions []*expr.Program
+	// compiled Task.ContentChange.IgnorePatterns, set when the job is scheduled
+	ignorePatterns []*regexp.Regexp
 }
 
 type Task struct {
-	URL              string        `json:"url"`
-	Timeout          time.Duration `json:"task,omitempty"`
-	AuthHeader       AuthHeader    `json:"auth_header,omitempty"`
-	ExpectedResponse any           `json:"expected_response,omitempty"`
+	URL              string            `json:"url"`
+	Method           string            `json:"method,omitempty"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	Body             string            `json:"body,omitempty"`
+	Timeout          time.Duration     `json:"task,omitempty"`
+	AuthHeader       AuthHeader        `json:"auth_header,omitempty"`
+	OAuth2           *OAuth2Config     `json:"oauth2,omitempty"`
+	Signing          *SigningConfig    `json:"signing,omitempty"`
+	TLS              *TLSOptions       `json:"tls,omitempty"`
+	FreshConnection  bool              `json:"fresh_connection,omitempty"`
+	Proxy            *ProxyConfig      `json:"proxy,omitempty"`
+	Redirect         *RedirectPolicy   `json:"redirect,omitempty"`
+	ResponseBody     *BodyOptions      `json:"response_body,omitempty"`
+	ContentChange    *ContentChange    `json:"content_change,omitempty"`
+	ExpectedResponse any               `json:"expected_response,omitempty"`
+	Assertions       []string          `json:"assertions,omitempty"`
+	Script           *ScriptTask       `json:"script,omitempty"`
+	TCP              *TCPTask          `json:"tcp,omitempty"`
+	DNS              *DNSTask          `json:"dns,omitempty"`
+	Certificate      *CertificateTask  `json:"certificate,omitempty"`
+	GRPC             *GRPCTask         `json:"grpc,omitempty"`
+	WebSocket        *WebSocketTask    `json:"websocket,omitempty"`
+	Heartbeat        *HeartbeatTask    `json:"heartbeat,omitempty"`
+	Scenario         *ScenarioTask     `json:"scenario,omitempty"`
 }
 
 type AuthHeader struct {
 	Scheme     string `json:"scheme,omitempty"`
 	Parameters string `json:"parameters,omitempty"`
+	Username   string `json:"username,omitempty"`
+	Password   string `json:"password,omitempty"`
+}
+
+type OAuth2Config struct {
+	TokenURL       string            `json:"token_url"`
+	ClientID       string            `json:"client_id"`
+	ClientSecret   string            `json:"client_secret"`
+	Scopes         []string          `json:"scopes,omitempty"`
+	EndpointParams map[string]string `json:"endpoint_params,omitempty"`
+}
+
+type SigningConfig struct {
+	Key             string `json:"key"`
+	Algorithm       string `json:"algorithm,omitempty"`
+	Encoding        string `json:"encoding,omitempty"`
+	SignatureHeader string `json:"signature_header,omitempty"`
+	SignaturePrefix string `json:"signature_prefix,omitempty"`
+	TimestampHeader string `json:"timestamp_header,omitempty"`
+	CanonicalFormat string `json:"canonical_format,omitempty"`
+}
+
+type TLSOptions struct {
+	ClientCertificate  string `json:"client_certificate,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	CABundle           string `json:"ca_bundle,omitempty"`
+	MinVersion         string `json:"min_version,omitempty"`
+	ServerName         string `json:"server_name,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+}
+
+type ProxyConfig struct {
+	URL      string `json:"url,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+}
+
+type RedirectPolicy struct {
+	Mode             string   `json:"mode,omitempty"`
+	MaxHops          int      `json:"max_hops,omitempty"`
+	ExpectedFinalURL string   `json:"expected_final_url,omitempty"`
+	ExpectedChain    []string `json:"expected_chain,omitempty"`
+}
+
+type BodyOptions struct {
+	MaxBytes      int64 `json:"max_bytes,omitempty"`
+	Snapshot      bool  `json:"snapshot,omitempty"`
+	SnapshotBytes int   `json:"snapshot_bytes,omitempty"`
+}
+
+type ContentChange struct {
+	IgnorePatterns []string `json:"ignore_patterns,omitempty"`
+}
+
+type ScriptTask struct {
+	Source      string        `json:"source"`
+	Timeout     time.Duration `json:"timeout,omitempty"`
+	MemoryLimit int64         `json:"memory_limit,omitempty"`
+}
+
+type TCPTask struct {
+	Address string `json:"address"`
+	Payload string `json:"payload,omitempty"`
+	Expect  string `json:"expect,omitempty"`
+}
+
+type DNSTask struct {
+	Name           string   `json:"name"`
+	RecordType     string   `json:"record_type"`
+	Resolver       string   `json:"resolver,omitempty"`
+	ExpectedValues []string `json:"expected_values,omitempty"`
+	MinAnswers     int      `json:"min_answers,omitempty"`
+}
+
+type CertificateTask struct {
+	Address     string `json:"address,omitempty"`
+	ServerName  string `json:"server_name,omitempty"`
+	WarningDays []int  `json:"warning_days,omitempty"`
+	FailureDays int    `json:"failure_days,omitempty"`
+}
+
+type GRPCTask struct {
+	Address            string            `json:"address"`
+	Service            string            `json:"service,omitempty"`
+	TLS                bool              `json:"tls,omitempty"`
+	ServerName         string            `json:"server_name,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+	Metadata           map[string]string `json:"metadata,omitempty"`
+}
+
+type WebSocketTask struct {
+	URL                string            `json:"url"`
+	Origin             string            `json:"origin,omitempty"`
+	Headers            map[string]string `json:"headers,omitempty"`
+	Subprotocols       []string          `json:"subprotocols,omitempty"`
+	InsecureSkipVerify bool              `json:"insecure_skip_verify,omitempty"`
+	Message            string            `json:"message,omitempty"`
+	Expect             string            `json:"expect,omitempty"`
+}
+
+type HeartbeatTask struct {
+	Token       string        `json:"token,omitempty"`
+	Schedule    string        `json:"schedule,omitempty"`
+	GracePeriod time.Duration `json:"grace_period,omitempty"`
+}
+
+type ScenarioTask struct {
+	Variables map[string]string `json:"variables,omitempty"`
+	Steps     []ScenarioStep    `json:"steps"`
+}
+
+type ScenarioStep struct {
+	Name             string            `json:"name,omitempty"`
+	Method           string            `json:"method,omitempty"`
+	URL              string            `json:"url"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	Body             string            `json:"body,omitempty"`
+	ExpectedStatus   int               `json:"expected_status,omitempty"`
+	BodyContains     string            `json:"body_contains,omitempty"`
+	ExpectedResponse any               `json:"expected_response,omitempty"`
+	Extract          []Extraction      `json:"extract,omitempty"`
+}
+
+type Extraction struct {
+	Variable string `json:"variable"`
+	Source   string `json:"source"`
+	Path     string `json:"path"`
+}
+
+type Verdict string
+
+const (
+	VerdictUp   Verdict = "up"
+	VerdictWarn Verdict = "warn"
+	VerdictDown Verdict = "down"
+)
+
+type RunResult struct {
+	JobUID     uuid.UUID        `json:"job_uid"`
+	ReplicaID  string           `json:"replica_id,omitempty"`
+	StartedAt  time.Time        `json:"started_at"`
+	Duration   time.Duration    `json:"duration_ns"`
+	Verdict    Verdict          `json:"verdict"`
+	StatusCode int              `json:"status_code,omitempty"`
+	Body       any              `json:"body,omitempty"`
+	Error      string           `json:"error,omitempty"`
+	TCP        *TCPResult       `json:"tcp,omitempty"`
+	DNS        *DNSResult       `json:"dns,omitempty"`
+	TLS        *TLSResult       `json:"tls,omitempty"`
+	GRPC       *GRPCResult      `json:"grpc,omitempty"`
+	WebSocket  *WebSocketResult `json:"websocket,omitempty"`
+	Heartbeat  *HeartbeatResult `json:"heartbeat,omitempty"`
+	Scenario   *ScenarioResult  `json:"scenario,omitempty"`
+	Timings    *HTTPTimings     `json:"timings,omitempty"`
+	Redirects  *RedirectResult  `json:"redirects,omitempty"`
+	Snapshot   *BodySnapshot    `json:"snapshot,omitempty"`
+
+	ContentChange *ContentChangeResult `json:"content_change,omitempty"`
+	Script        *ScriptResult        `json:"script,omitempty"`
+	// set on stored results that were too large to keep in full
+	Truncated bool `json:"truncated,omitempty"`
+
+	// normalized body compared against the previous run, never stored
+	content string
+}
+
+type TCPResult struct {
+	ConnectLatency time.Duration `json:"connect_latency_ns"`
+	Response       string        `json:"response,omitempty"`
+}
+
+type DNSResult struct {
+	Answers        []string      `json:"answers"`
+	ResolutionTime time.Duration `json:"resolution_time_ns"`
+}
+
+type TLSResult struct {
+	Subject          string            `json:"subject"`
+	Issuer           string            `json:"issuer"`
+	NotAfter         time.Time         `json:"not_after"`
+	DaysRemaining    int               `json:"days_remaining"`
+	WarningThreshold int               `json:"warning_threshold,omitempty"`
+	Chain            []CertificateInfo `json:"chain,omitempty"`
+}
+
+type CertificateInfo struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+type GRPCResult struct {
+	Status string `json:"status"`
+}
+
+type WebSocketResult struct {
+	HandshakeLatency time.Duration `json:"handshake_latency_ns"`
+	RoundTripLatency time.Duration `json:"round_trip_latency_ns,omitempty"`
+	Response         string        `json:"response,omitempty"`
+}
+
+type HeartbeatResult struct {
+	LastPing   *time.Time `json:"last_ping,omitempty"`
+	ExpectedBy time.Time  `json:"expected_by"`
+}
+
+type ScenarioResult struct {
+	Steps []StepResult `json:"steps"`
+}
+
+type StepResult struct {
+	Name       string        `json:"name"`
+	StatusCode int           `json:"status_code,omitempty"`
+	Duration   time.Duration `json:"duration_ns"`
+	Verdict    Verdict       `json:"verdict"`
+	Error      string        `json:"error,omitempty"`
+}
+
+type HTTPTimings struct {
+	DNSLookup        time.Duration `json:"dns_lookup_ns"`
+	TCPConnect       time.Duration `json:"tcp_connect_ns"`
+	TLSHandshake     time.Duration `json:"tls_handshake_ns"`
+	TimeToFirstByte  time.Duration `json:"time_to_first_byte_ns"`
+	Transfer         time.Duration `json:"transfer_ns"`
+	ConnectionReused bool          `json:"connection_reused"`
+}
+
+type RedirectResult struct {
+	FinalURL string        `json:"final_url"`
+	Chain    []RedirectHop `json:"chain,omitempty"`
+}
+
+type RedirectHop struct {
+	URL        string `json:"url"`
+	StatusCode int    `json:"status_code"`
+	Location   string `json:"location"`
+}
+
+type BodySnapshot struct {
+	ContentType     string `json:"content_type,omitempty"`
+	ContentEncoding string `json:"content_encoding,omitempty"`
+	Size            int    `json:"size"`
+	Truncated       bool   `json:"truncated,omitempty"`
+	Content         string `json:"content"`
+	Base64          bool   `json:"base64,omitempty"`
+}
+
+type ScriptResult struct {
+	Duration time.Duration      `json:"duration_ns"`
+	Metrics  map[string]float64 `json:"metrics,omitempty"`
+}
+
+type ContentChangeResult struct {
+	Hash         string `json:"hash"`
+	PreviousHash string `json:"previous_hash,omitempty"`
+	Changed      bool   `json:"changed,omitempty"`
 }
@@ -0,0 +1,170 @@
+package automators
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+type webSocketChecker struct{}
+
+func NewWebSocketChecker() Checker {
+	return &webSocketChecker{}
+}
+
+func (w *webSocketChecker) Validate(task Task) error {
+	if task.WebSocket == nil {
+		return fmt.Errorf("websocket check requires websocket settings")
+	}
+
+	location, err := url.Parse(task.WebSocket.URL)
+	if err != nil {
+		return fmt.Errorf("invalid websocket url: %w", err)
+	}
+
+	if location.Scheme != "ws" && location.Scheme != "wss" {
+		return fmt.Errorf("websocket url must use ws or wss scheme")
+	}
+
+	if location.Hostname() == "" {
+		return fmt.Errorf("websocket url has no host")
+	}
+
+	if task.WebSocket.InsecureSkipVerify && location.Scheme != "wss" {
+		return fmt.Errorf("insecure skip verify requires wss")
+	}
+
+	if task.WebSocket.Expect != "" && task.WebSocket.Message == "" {
+		return fmt.Errorf("websocket expect requires a message to send")
+	}
+	return nil
+}
+
+func (w *webSocketChecker) Execute(ctx context.Context, config *JobConfig) (*RunResult, error) {
+	task := config.Task.WebSocket
+	if task == nil {
+		return nil, fmt.Errorf("websocket check requires websocket settings")
+	}
+
+	wsConfig, err := newWebSocketConfig(task)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	conn, err := dialWebSocket(ctx, wsConfig, task.InsecureSkipVerify, config.Task.Proxy)
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("error setting deadline: %w", err)
+		}
+	}
+
+	ws, err := websocket.NewClient(wsConfig, conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error performing websocket handshake: %w", err)
+	}
+	defer ws.Close()
+
+	result := &RunResult{
+		Verdict: VerdictUp,
+		WebSocket: &WebSocketResult{
+			HandshakeLatency: time.Since(start),
+		},
+	}
+
+	if task.Message == "" {
+		return result, nil
+	}
+
+	sent := time.Now()
+	if err := websocket.Message.Send(ws, task.Message); err != nil {
+		return nil, fmt.Errorf("error sending message: %w", err)
+	}
+
+	var reply string
+	if err := websocket.Message.Receive(ws, &reply); err != nil {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("no reply received: %s", err)
+		return result, nil
+	}
+	result.WebSocket.RoundTripLatency = time.Since(sent)
+	result.WebSocket.Response = reply
+
+	if task.Expect != "" && !strings.Contains(reply, task.Expect) {
+		result.Verdict = VerdictDown
+		result.Error = fmt.Sprintf("expected reply %q not received", task.Expect)
+	}
+	return result, nil
+}
+
+func newWebSocketConfig(task *WebSocketTask) (*websocket.Config, error) {
+	location, err := url.Parse(task.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid websocket url: %w", err)
+	}
+
+	origin := task.Origin
+	if origin == "" {
+		scheme := "http"
+		if location.Scheme == "wss" {
+			scheme = "https"
+		}
+		origin = scheme + "://" + location.Host
+	}
+
+	config, err := websocket.NewConfig(task.URL, origin)
+	if err != nil {
+		return nil, fmt.Errorf("error building websocket config: %w", err)
+	}
+
+	config.Protocol = task.Subprotocols
+	config.Header = make(http.Header)
+	for key, value := range task.Headers {
+		config.Header.Set(key, value)
+	}
+	return config, nil
+}
+
+func dialWebSocket(ctx context.Context, config *websocket.Config, insecureSkipVerify bool, proxy *ProxyConfig) (net.Conn, error) {
+	location := config.Location
+	address := location.Host
+	if location.Port() == "" {
+		port := "80"
+		if location.Scheme == "wss" {
+			port = "443"
+		}
+		address = net.JoinHostPort(location.Hostname(), port)
+	}
+
+	conn, err := dialContext(ctx, proxy, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting: %w", err)
+	}
+
+	if location.Scheme != "wss" {
+		return conn, nil
+	}
+
+	tlsConn := tls.Client(conn, &tls.Config{
+		ServerName:         location.Hostname(),
+		InsecureSkipVerify: insecureSkipVerify,
+	})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("error performing tls handshake: %w", err)
+	}
+	return tlsConn, nil
+}
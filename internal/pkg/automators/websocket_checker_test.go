@@ -0,0 +1,182 @@
+package automators_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"golang.org/x/net/websocket"
+)
+
+func startWebSocketServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	handler := websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			if r.Header.Get("Authorization") == "Bearer denied" {
+				return fmt.Errorf("unauthorized")
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			var msg string
+			if err := websocket.Message.Receive(ws, &msg); err != nil {
+				return
+			}
+
+			switch msg {
+			case "silent":
+				time.Sleep(time.Second)
+			case "whoami":
+				websocket.Message.Send(ws, "token="+ws.Request().Header.Get("Authorization"))
+			default:
+				websocket.Message.Send(ws, "echo: "+msg)
+			}
+		},
+	}
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWebSocketChecker_Execute(t *testing.T) {
+	t.Parallel()
+
+	server := startWebSocketServer(t)
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	tests := []struct {
+		name         string
+		task         automators.WebSocketTask
+		wantVerdict  automators.Verdict
+		wantResponse string
+		wantErr      bool
+	}{
+		{
+			name:        "handshake only",
+			task:        automators.WebSocketTask{URL: wsURL},
+			wantVerdict: automators.VerdictUp,
+		},
+		{
+			name:         "echo matches",
+			task:         automators.WebSocketTask{URL: wsURL, Message: "ping", Expect: "echo: ping"},
+			wantVerdict:  automators.VerdictUp,
+			wantResponse: "echo: ping",
+		},
+		{
+			name:         "echo mismatch",
+			task:         automators.WebSocketTask{URL: wsURL, Message: "ping", Expect: "pong"},
+			wantVerdict:  automators.VerdictDown,
+			wantResponse: "echo: ping",
+		},
+		{
+			name:         "custom headers",
+			task:         automators.WebSocketTask{URL: wsURL, Headers: map[string]string{"Authorization": "Bearer abc"}, Message: "whoami", Expect: "Bearer abc"},
+			wantVerdict:  automators.VerdictUp,
+			wantResponse: "token=Bearer abc",
+		},
+		{
+			name:        "no reply within timeout",
+			task:        automators.WebSocketTask{URL: wsURL, Message: "silent", Expect: "anything"},
+			wantVerdict: automators.VerdictDown,
+		},
+		{
+			name:    "handshake rejected",
+			task:    automators.WebSocketTask{URL: wsURL, Headers: map[string]string{"Authorization": "Bearer denied"}},
+			wantErr: true,
+		},
+		{
+			name:    "connection refused",
+			task:    automators.WebSocketTask{URL: "ws://127.0.0.1:1/socket"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+
+			task := tt.task
+			got, err := automators.NewWebSocketChecker().Execute(ctx, &automators.JobConfig{
+				Type: automators.CheckTypeWebSocket,
+				Task: automators.Task{WebSocket: &task},
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("webSocketChecker.Execute() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Verdict != tt.wantVerdict {
+				t.Errorf("webSocketChecker.Execute() verdict = %v, want %v (%s)", got.Verdict, tt.wantVerdict, got.Error)
+			}
+			if got.WebSocket == nil || got.WebSocket.HandshakeLatency <= 0 {
+				t.Errorf("webSocketChecker.Execute() handshake latency not reported: %+v", got.WebSocket)
+				return
+			}
+			if got.WebSocket.Response != tt.wantResponse {
+				t.Errorf("webSocketChecker.Execute() response = %q, want %q", got.WebSocket.Response, tt.wantResponse)
+			}
+			if tt.wantResponse != "" && got.WebSocket.RoundTripLatency <= 0 {
+				t.Errorf("webSocketChecker.Execute() round trip latency not reported")
+			}
+		})
+	}
+}
+
+func TestWebSocketChecker_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		task    automators.Task
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			task:    automators.Task{WebSocket: &automators.WebSocketTask{URL: "wss://gateway.internal/realtime", Message: "ping", Expect: "pong"}},
+			wantErr: false,
+		},
+		{
+			name:    "missing settings",
+			task:    automators.Task{},
+			wantErr: true,
+		},
+		{
+			name:    "http scheme",
+			task:    automators.Task{WebSocket: &automators.WebSocketTask{URL: "https://gateway.internal/realtime"}},
+			wantErr: true,
+		},
+		{
+			name:    "insecure skip verify without tls",
+			task:    automators.Task{WebSocket: &automators.WebSocketTask{URL: "ws://gateway.internal/realtime", InsecureSkipVerify: true}},
+			wantErr: true,
+		},
+		{
+			name:    "expect without message",
+			task:    automators.Task{WebSocket: &automators.WebSocketTask{URL: "ws://gateway.internal/realtime", Expect: "pong"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if err := automators.NewWebSocketChecker().Validate(tt.task); (err != nil) != tt.wantErr {
+				t.Errorf("webSocketChecker.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
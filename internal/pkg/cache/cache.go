@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strconv"
 
 	"github.com/go-redis/redis/v8"
 	"go.uber.org/zap"
@@ -53,6 +54,26 @@ func (c *Cache) GetData(ctx context.Context, key string) (string, error) {
 	logger.Debugw("retrieved data from redis cache", "data", result)
 	return result, nil
 }
+
+// LookupData reads a key that may legitimately be missing, such as state
+// written on first use. A missing key is reported as not found rather than as
+// an error, and is not logged.
+func (c *Cache) LookupData(ctx context.Context, key string) (string, bool, error) {
+	logger := c.logger.With("context", ctx)
+	result, err := c.redisClient.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return "", false, nil
+	}
+
+	if err != nil {
+		err := fmt.Errorf("error retrieving data from redis: %w", err)
+		logger.Error(err.Error())
+		return "", false, err
+	}
+	logger.Debugw("retrieved data from redis cache", "data", result)
+	return result, true, nil
+}
+
 func (c *Cache) DeleteData(ctx context.Context, key string) error {
 	_, err := c.redisClient.Del(ctx, key).Result()
 	if err != nil {
@@ -109,3 +130,124 @@ func (c *Cache) UpdateSet(ctx context.Context, setName string, keys ...string) e
 	}
 	return nil
 }
+
+func (c *Cache) UpdateSortedSet(ctx context.Context, setName, member string, score float64) error {
+
+	if _, err := c.redisClient.ZAdd(ctx, setName, &redis.Z{Score: score, Member: member}).Result(); err != nil {
+		err := fmt.Errorf("error updating sorted set: %w", err)
+		c.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) GetSortedSetByScore(ctx context.Context, setName string, min, max float64) ([]string, error) {
+
+	members, err := c.redisClient.ZRangeByScore(ctx, setName, &redis.ZRangeBy{
+		Min: strconv.FormatFloat(min, 'f', -1, 64),
+		Max: strconv.FormatFloat(max, 'f', -1, 64),
+	}).Result()
+	if err != nil && err != redis.Nil {
+		err := fmt.Errorf("error retrieving sorted set: %w", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+	return members, nil
+}
+
+func (c *Cache) DeleteFromSortedSet(ctx context.Context, setName string, members ...string) error {
+
+	if _, err := c.redisClient.ZRem(ctx, setName, members).Result(); err != nil && err != redis.Nil {
+		err := fmt.Errorf("error removing elements from sorted set: %w", err)
+		c.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) DeleteFromSortedSetByScore(ctx context.Context, setName string, min, max float64) error {
+
+	_, err := c.redisClient.ZRemRangeByScore(ctx, setName,
+		strconv.FormatFloat(min, 'f', -1, 64),
+		strconv.FormatFloat(max, 'f', -1, 64),
+	).Result()
+	if err != nil && err != redis.Nil {
+		err := fmt.Errorf("error removing range from sorted set: %w", err)
+		c.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) Publish(ctx context.Context, channel, message string) error {
+
+	if _, err := c.redisClient.Publish(ctx, channel, message).Result(); err != nil {
+		err := fmt.Errorf("error publishing message: %w", err)
+		c.logger.Error(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+
+	pubsub := c.redisClient.Subscribe(ctx, channel)
+	if _, err := pubsub.Receive(ctx); err != nil {
+		pubsub.Close()
+		err := fmt.Errorf("error subscribing to channel: %w", err)
+		c.logger.Error(err)
+		return nil, err
+	}
+
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		redisMessages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-redisMessages:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return messages, nil
+}
+
+func (c *Cache) PushToList(ctx context.Context, key, data string, maxLen int64) error {
+
+	pipe := c.redisClient.TxPipeline()
+	pipe.LPush(ctx, key, data)
+	if maxLen > 0 {
+		pipe.LTrim(ctx, key, 0, maxLen-1)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		err := fmt.Errorf("error pushing data to list: %w", err)
+		c.logger.With("context", ctx).Error(err)
+		return err
+	}
+	return nil
+}
+
+func (c *Cache) GetList(ctx context.Context, key string, start, stop int64) ([]string, error) {
+
+	list, err := c.redisClient.LRange(ctx, key, start, stop).Result()
+	if err != nil && err != redis.Nil {
+		err := fmt.Errorf("error retrieving list: %w", err)
+		c.logger.With("context", ctx).Error(err)
+		return nil, err
+	}
+	return list, nil
+}
@@ -3,7 +3,10 @@ package cache_test
 import (
 	"context"
 	"fmt"
+	"math"
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis/v8"
@@ -199,3 +202,277 @@ func TestCache_GetData(t *testing.T) {
 		})
 	}
 }
+
+func TestCache_LookupData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		key       string
+		stored    bool
+		want      string
+		wantFound bool
+		wantErr   bool
+	}{
+		{
+			name:      "successful retrieval",
+			key:       "test-key",
+			stored:    true,
+			want:      "test-value",
+			wantFound: true,
+		},
+		{
+			name:      "missing key",
+			key:       "test-key",
+			wantFound: false,
+		},
+		{
+			name:    "retrieval error",
+			key:     "test-key",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			miniRed := miniredis.RunT(t)
+
+			if tt.wantErr {
+				miniRed.SetError(fmt.Sprintf("%s error", tt.name))
+			} else if tt.stored {
+				if err := miniRed.Set(tt.key, tt.want); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			c := cache.NewCache(redis.NewClient(&redis.Options{
+				Addr: miniRed.Addr(),
+			}), zap.NewExample().Sugar())
+
+			got, found, err := c.LookupData(ctx, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Cache.LookupData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || found != tt.wantFound {
+				t.Errorf("Cache.LookupData() = %q, %v, want %q, %v", got, found, tt.want, tt.wantFound)
+			}
+		})
+	}
+}
+
+func TestCache_SortedSet(t *testing.T) {
+	t.Parallel()
+
+	type member struct {
+		name  string
+		score float64
+	}
+	tests := []struct {
+		name     string
+		members  []member
+		min      float64
+		max      float64
+		want     []string
+		wantErr  bool
+		redisErr bool
+	}{
+		{
+			name: "members in score range",
+			members: []member{
+				{name: "replica-a", score: 100},
+				{name: "replica-b", score: 200},
+				{name: "replica-c", score: 300},
+			},
+			min:  150,
+			max:  math.Inf(1),
+			want: []string{"replica-b", "replica-c"},
+		},
+		{
+			name:    "empty set",
+			members: nil,
+			min:     0,
+			max:     math.Inf(1),
+			want:    []string{},
+		},
+		{
+			name:     "retrieval error",
+			min:      0,
+			max:      math.Inf(1),
+			wantErr:  true,
+			redisErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			miniRed := miniredis.RunT(t)
+
+			c := cache.NewCache(redis.NewClient(&redis.Options{
+				Addr: miniRed.Addr(),
+			}), zap.NewExample().Sugar())
+
+			for _, m := range tt.members {
+				if err := c.UpdateSortedSet(ctx, "replicas_set", m.name, m.score); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if tt.redisErr {
+				miniRed.SetError(fmt.Sprintf("%s error", tt.name))
+			}
+
+			got, err := c.GetSortedSetByScore(ctx, "replicas_set", tt.min, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Cache.GetSortedSetByScore() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cache.GetSortedSetByScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCache_DeleteFromSortedSetByScore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	miniRed := miniredis.RunT(t)
+
+	c := cache.NewCache(redis.NewClient(&redis.Options{
+		Addr: miniRed.Addr(),
+	}), zap.NewExample().Sugar())
+
+	for score, name := range []string{"replica-a", "replica-b", "replica-c"} {
+		if err := c.UpdateSortedSet(ctx, "replicas_set", name, float64(score)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := c.DeleteFromSortedSetByScore(ctx, "replicas_set", math.Inf(-1), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteFromSortedSet(ctx, "replicas_set", "replica-c"); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.GetSortedSetByScore(ctx, "replicas_set", math.Inf(-1), math.Inf(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"replica-b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Cache.GetSortedSetByScore() = %v, want %v", got, want)
+	}
+}
+
+func TestCache_PublishSubscribe(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	miniRed := miniredis.RunT(t)
+
+	c := cache.NewCache(redis.NewClient(&redis.Options{
+		Addr: miniRed.Addr(),
+	}), zap.NewExample().Sugar())
+
+	messages, err := c.Subscribe(ctx, "jobs_events")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Publish(ctx, "jobs_events", "test-message"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case got := <-messages:
+		if got != "test-message" {
+			t.Errorf("Cache.Subscribe() = %v, want %v", got, "test-message")
+		}
+	case <-ctx.Done():
+		t.Fatal("Cache.Subscribe() did not receive published message")
+	}
+
+	cancel()
+	for range messages {
+	}
+}
+
+func TestCache_PushToList(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		pushes  []string
+		maxLen  int64
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "newest first",
+			pushes: []string{"first", "second", "third"},
+			maxLen: 10,
+			want:   []string{"third", "second", "first"},
+		},
+		{
+			name:   "trimmed to max length",
+			pushes: []string{"first", "second", "third"},
+			maxLen: 2,
+			want:   []string{"third", "second"},
+		},
+		{
+			name:    "push error",
+			pushes:  []string{"first"},
+			maxLen:  2,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			miniRed := miniredis.RunT(t)
+
+			if tt.wantErr {
+				miniRed.SetError(fmt.Sprintf("%s error", tt.name))
+			}
+
+			c := cache.NewCache(redis.NewClient(&redis.Options{
+				Addr: miniRed.Addr(),
+			}), zap.NewExample().Sugar())
+
+			for _, p := range tt.pushes {
+				err := c.PushToList(ctx, "job_results:test", p, tt.maxLen)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Cache.PushToList() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := c.GetList(ctx, "job_results:test", 0, -1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Cache.GetList() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+package expr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+type function struct {
+	arity int
+	call  func(args []any) (any, error)
+}
+
+var functions = map[string]function{
+	"len":      {arity: 1, call: length},
+	"contains": {arity: 2, call: contains},
+	"matches":  {arity: 2, call: matches},
+}
+
+func (p *Program) eval(n node, env map[string]any) (any, error) {
+	value, err := p.evalNode(n, env)
+	if err != nil {
+		if _, ok := err.(*EvalError); ok {
+			return nil, err
+		}
+		return nil, &EvalError{Expr: p.text(n), Err: err}
+	}
+	return value, nil
+}
+
+func (p *Program) evalNode(n node, env map[string]any) (any, error) {
+	switch n := n.(type) {
+	case *literal:
+		return n.value, nil
+
+	case *identifier:
+		return normalize(env[n.name]), nil
+
+	case *member:
+		object, err := p.eval(n.object, env)
+		if err != nil {
+			return nil, err
+		}
+		return field(object, n.name)
+
+	case *index:
+		object, err := p.eval(n.object, env)
+		if err != nil {
+			return nil, err
+		}
+		key, err := p.eval(n.key, env)
+		if err != nil {
+			return nil, err
+		}
+		return element(object, key)
+
+	case *call:
+		args := make([]any, 0, len(n.args))
+		for _, arg := range n.args {
+			value, err := p.eval(arg, env)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, value)
+		}
+		if n.pattern != nil {
+			return match(args[0], n.pattern)
+		}
+		return functions[n.name].call(args)
+
+	case *unary:
+		operand, err := p.eval(n.operand, env)
+		if err != nil {
+			return nil, err
+		}
+		if n.op == "!" {
+			b, ok := operand.(bool)
+			if !ok {
+				return nil, fmt.Errorf("cannot negate %s", typeName(operand))
+			}
+			return !b, nil
+		}
+		number, ok := operand.(float64)
+		if !ok {
+			return nil, fmt.Errorf("cannot negate %s", typeName(operand))
+		}
+		return -number, nil
+
+	case *binary:
+		return p.evalBinary(n, env)
+	}
+	return nil, fmt.Errorf("unknown expression")
+}
+
+func (p *Program) evalBinary(n *binary, env map[string]any) (any, error) {
+	left, err := p.eval(n.left, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if n.op == "&&" || n.op == "||" {
+		l, ok := left.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s requires booleans, got %s", n.op, typeName(left))
+		}
+		if (n.op == "&&" && !l) || (n.op == "||" && l) {
+			return l, nil
+		}
+		right, err := p.eval(n.right, env)
+		if err != nil {
+			return nil, err
+		}
+		r, ok := right.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s requires booleans, got %s", n.op, typeName(right))
+		}
+		return r, nil
+	}
+
+	right, err := p.eval(n.right, env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==":
+		return reflect.DeepEqual(left, right), nil
+	case "!=":
+		return !reflect.DeepEqual(left, right), nil
+	case "<", "<=", ">", ">=":
+		return compare(n.op, left, right)
+	case "+":
+		if l, ok := left.(string); ok {
+			if r, ok := right.(string); ok {
+				return l + r, nil
+			}
+		}
+	}
+
+	l, lok := left.(float64)
+	r, rok := right.(float64)
+	if !lok || !rok {
+		return nil, fmt.Errorf("cannot apply %s to %s and %s", n.op, typeName(left), typeName(right))
+	}
+
+	switch n.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return l / r, nil
+	case "%":
+		if r == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(l, r), nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+func compare(op string, left, right any) (bool, error) {
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false, fmt.Errorf("cannot compare number with %s", typeName(right))
+		}
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false, fmt.Errorf("cannot compare string with %s", typeName(right))
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return false, fmt.Errorf("cannot order %s", typeName(left))
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	}
+	return cmp >= 0, nil
+}
+
+func field(object any, name string) (any, error) {
+	switch o := object.(type) {
+	case map[string]any:
+		return normalize(o[name]), nil
+	case Getter:
+		value, _ := o.Get(name)
+		return normalize(value), nil
+	}
+	return nil, fmt.Errorf("cannot read field %q of %s", name, typeName(object))
+}
+
+func element(object, key any) (any, error) {
+	if name, ok := key.(string); ok {
+		return field(object, name)
+	}
+
+	position, ok := key.(float64)
+	if !ok || position != math.Trunc(position) {
+		return nil, fmt.Errorf("index must be a string or an integer, got %s", formatValue(key))
+	}
+
+	items, ok := object.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot index %s", typeName(object))
+	}
+	// bounds are checked on the float, huge indexes overflow int
+	if position < 0 || position >= float64(len(items)) {
+		return nil, fmt.Errorf("index %s out of range for array of length %d", formatValue(key), len(items))
+	}
+	return normalize(items[int(position)]), nil
+}
+
+func length(args []any) (any, error) {
+	switch v := args[0].(type) {
+	case string:
+		return float64(utf8.RuneCountInString(v)), nil
+	case []any:
+		return float64(len(v)), nil
+	case map[string]any:
+		return float64(len(v)), nil
+	}
+	return nil, fmt.Errorf("cannot take the length of %s", typeName(args[0]))
+}
+
+func contains(args []any) (any, error) {
+	switch haystack := args[0].(type) {
+	case string:
+		needle, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot search a string for %s", typeName(args[1]))
+		}
+		return strings.Contains(haystack, needle), nil
+	case []any:
+		for _, item := range haystack {
+			if reflect.DeepEqual(normalize(item), args[1]) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]any:
+		key, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("object keys are strings, got %s", typeName(args[1]))
+		}
+		_, found := haystack[key]
+		return found, nil
+	}
+	return nil, fmt.Errorf("cannot search %s", typeName(args[0]))
+}
+
+// matches compiles patterns built at evaluation time, literal patterns are
+// compiled by the parser.
+func matches(args []any) (any, error) {
+	if _, ok := args[0].(string); !ok {
+		return nil, fmt.Errorf("matches expects a string, got %s", typeName(args[0]))
+	}
+	pattern, ok := args[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("matches expects a string pattern, got %s", typeName(args[1]))
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern: %w", err)
+	}
+	return match(args[0], re)
+}
+
+func match(value any, re *regexp.Regexp) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("matches expects a string, got %s", typeName(value))
+	}
+	return re.MatchString(s), nil
+}
+
+// normalize converts Go numbers to float64 so they compare equal to decoded
+// JSON numbers and literals.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint:
+		return float64(v)
+	case uint32:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+func typeName(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any, Getter:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
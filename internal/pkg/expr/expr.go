@@ -0,0 +1,168 @@
+// Package expr implements a small boolean expression language for asserting
+// on check responses, e.g. `status == 200 && len(body.items) > 0`.
+//
+// Expressions support number, string, true, false and null literals, field
+// access with `.` and `[]`, the operators ! - * / % + < <= > >= == != && ||
+// and the functions len, contains and matches.
+package expr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Getter exposes a lookup, such as case insensitive headers, as an object.
+type Getter interface {
+	Get(key string) (any, bool)
+}
+
+type Program struct {
+	source string
+	root   node
+}
+
+type SyntaxError struct {
+	Source string
+	Pos    int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d of %q: %s", e.Pos+1, e.Source, e.Msg)
+}
+
+// EvalError is returned when a sub-expression cannot be evaluated, such as
+// reading a field of null or comparing a string with a number.
+type EvalError struct {
+	Expr string
+	Err  error
+}
+
+func (e *EvalError) Error() string {
+	return fmt.Sprintf("error evaluating %s: %s", e.Expr, e.Err)
+}
+
+func (e *EvalError) Unwrap() error {
+	return e.Err
+}
+
+// Failure is returned when an expression evaluates to false. Expr is the
+// sub-expression responsible and Values holds the operands it compared.
+type Failure struct {
+	Expr   string
+	Values []string
+}
+
+func (e *Failure) Error() string {
+	if len(e.Values) == 0 {
+		return fmt.Sprintf("%s is false", e.Expr)
+	}
+	return fmt.Sprintf("%s is false (%s)", e.Expr, strings.Join(e.Values, ", "))
+}
+
+// Compile parses an expression. When variables are given, any other variable
+// name is rejected.
+func Compile(source string, variables ...string) (*Program, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, withSource(err, source)
+	}
+
+	p := &parser{tokens: tokens}
+	if len(variables) > 0 {
+		p.variables = make(map[string]struct{}, len(variables))
+		for _, variable := range variables {
+			p.variables[variable] = struct{}{}
+		}
+	}
+
+	root, err := p.parseExpression(1)
+	if err != nil {
+		return nil, withSource(err, source)
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, withSource(p.unexpected("expected an operator"), source)
+	}
+	return &Program{source: source, root: root}, nil
+}
+
+func withSource(err error, source string) error {
+	if syntaxErr, ok := err.(*SyntaxError); ok {
+		syntaxErr.Source = source
+	}
+	return err
+}
+
+func (p *Program) String() string {
+	return p.source
+}
+
+// Eval returns nil when the expression is true against env, a *Failure when
+// it is false and an *EvalError when it cannot be evaluated.
+func (p *Program) Eval(env map[string]any) error {
+	value, err := p.eval(p.root, env)
+	if err != nil {
+		return err
+	}
+
+	result, ok := value.(bool)
+	if !ok {
+		return &EvalError{Expr: p.source, Err: fmt.Errorf("result is %s, not a boolean", typeName(value))}
+	}
+	if result {
+		return nil
+	}
+	return p.explain(p.root, env)
+}
+
+// explain walks down && chains to the operand that was false.
+func (p *Program) explain(n node, env map[string]any) error {
+	if b, ok := n.(*binary); ok && b.op == "&&" {
+		if left, err := p.eval(b.left, env); err == nil && left == false {
+			return p.explain(b.left, env)
+		}
+		return p.explain(b.right, env)
+	}
+
+	var operands []node
+	switch n := n.(type) {
+	case *binary:
+		operands = []node{n.left, n.right}
+	case *unary:
+		operands = []node{n.operand}
+	case *call:
+		operands = n.args
+	default:
+		operands = []node{n}
+	}
+
+	failure := &Failure{Expr: p.text(n)}
+	for _, operand := range operands {
+		if _, ok := operand.(*literal); ok {
+			continue
+		}
+		value, err := p.eval(operand, env)
+		if err != nil {
+			continue
+		}
+		failure.Values = append(failure.Values, fmt.Sprintf("%s = %s", p.text(operand), formatValue(value)))
+	}
+	return failure
+}
+
+func (p *Program) text(n node) string {
+	s := n.span()
+	return p.source[s.start:s.end]
+}
+
+func formatValue(value any) string {
+	if getter, ok := value.(Getter); ok {
+		return fmt.Sprintf("%v", getter)
+	}
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(bytes)
+}
@@ -0,0 +1,214 @@
+package expr_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/expr"
+)
+
+type headers map[string]string
+
+func (h headers) Get(key string) (any, bool) {
+	value, ok := h[strings.ToLower(key)]
+	return value, ok
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		source  string
+		wantErr string
+	}{
+		{
+			name:   "comparison chain",
+			source: `len(body.items) > 0 && body.status == 'ok'`,
+		},
+		{
+			name:   "index and call",
+			source: `contains(body.items[0].tags, "a") || !matches(headers["Content-Type"], "^text/")`,
+		},
+		{
+			name:    "unclosed call",
+			source:  `len(body.items > 0`,
+			wantErr: `column 19`,
+		},
+		{
+			name:    "unknown variable",
+			source:  `bdy.status == "ok"`,
+			wantErr: `unknown variable "bdy"`,
+		},
+		{
+			name:    "unknown function",
+			source:  `size(body) > 0`,
+			wantErr: `unknown function "size"`,
+		},
+		{
+			name:    "wrong argument count",
+			source:  `contains(body)`,
+			wantErr: `contains expects 2 arguments, got 1`,
+		},
+		{
+			name:    "invalid pattern",
+			source:  `matches(body.name, "(")`,
+			wantErr: `invalid pattern`,
+		},
+		{
+			name:    "unterminated string",
+			source:  `body.status == "ok`,
+			wantErr: `unterminated string`,
+		},
+		{
+			name:    "trailing tokens",
+			source:  `status == 200 200`,
+			wantErr: `expected an operator, found "200"`,
+		},
+		{
+			name:    "empty expression",
+			source:  ``,
+			wantErr: `expected a value, found end of expression`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := expr.Compile(tt.source, "status", "headers", "body")
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Compile() error = %v", err)
+				}
+				return
+			}
+
+			var syntaxErr *expr.SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Compile() error = %v, want SyntaxError", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %q, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestProgram_Eval(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]any{
+		"status":  200,
+		"latency": 12.5,
+		"headers": headers{"content-type": "application/json"},
+		"body": map[string]any{
+			"status":  "degraded",
+			"pattern": "^deg",
+			"items":   []any{map[string]any{"name": "a", "count": float64(2)}},
+			"empty":   []any{},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		source      string
+		wantFailure string
+		wantEvalErr string
+	}{
+		{
+			name:   "true expression",
+			source: `status == 200 && len(body.items) > 0 && body.items[0].count * 2 == 4`,
+		},
+		{
+			name:   "headers and functions",
+			source: `matches(headers["Content-Type"], "json$") && contains(body, "items") && latency < 100`,
+		},
+		{
+			name:   "pattern built at evaluation time",
+			source: `matches(body.status, body.pattern) && matches(body.status, "^" + "deg")`,
+		},
+		{
+			name:        "literal pattern on a non string",
+			source:      `matches(status, "^2")`,
+			wantEvalErr: `matches expects a string, got number`,
+		},
+		{
+			name:   "or and not",
+			source: `!(status >= 500) || body.status == "ok"`,
+		},
+		{
+			name:   "missing field is null",
+			source: `body.missing == null`,
+		},
+		{
+			name:        "failing operand of and chain",
+			source:      `len(body.items) > 0 && body.status == 'ok'`,
+			wantFailure: `body.status == 'ok' is false (body.status = "degraded")`,
+		},
+		{
+			name:        "failing call",
+			source:      `contains(body.items[0].name, "b")`,
+			wantFailure: `contains(body.items[0].name, "b") is false (body.items[0].name = "a")`,
+		},
+		{
+			name:        "index out of range",
+			source:      `body.empty[0].name == "a"`,
+			wantEvalErr: `error evaluating body.empty[0]: index 0 out of range for array of length 0`,
+		},
+		{
+			name:        "index past int range",
+			source:      `body.items[100000000000000000000].name == "a"`,
+			wantEvalErr: `index 100000000000000000000 out of range for array of length 1`,
+		},
+		{
+			name:        "field of null",
+			source:      `body.missing.name == "a"`,
+			wantEvalErr: `error evaluating body.missing.name: cannot read field "name" of null`,
+		},
+		{
+			name:        "mismatched comparison",
+			source:      `body.status > 1`,
+			wantEvalErr: `cannot compare string with number`,
+		},
+		{
+			name:        "non boolean result",
+			source:      `status + 1`,
+			wantEvalErr: `result is number, not a boolean`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			program, err := expr.Compile(tt.source)
+			if err != nil {
+				t.Fatalf("Compile() error = %v", err)
+			}
+
+			err = program.Eval(env)
+			switch {
+			case tt.wantFailure != "":
+				var failure *expr.Failure
+				if !errors.As(err, &failure) {
+					t.Fatalf("Program.Eval() error = %v, want Failure", err)
+				}
+				if err.Error() != tt.wantFailure {
+					t.Errorf("Program.Eval() error = %q, want %q", err, tt.wantFailure)
+				}
+			case tt.wantEvalErr != "":
+				var evalErr *expr.EvalError
+				if !errors.As(err, &evalErr) {
+					t.Fatalf("Program.Eval() error = %v, want EvalError", err)
+				}
+				if !strings.Contains(err.Error(), tt.wantEvalErr) {
+					t.Errorf("Program.Eval() error = %q, want it to contain %q", err, tt.wantEvalErr)
+				}
+			case err != nil:
+				t.Errorf("Program.Eval() error = %v", err)
+			}
+		})
+	}
+}
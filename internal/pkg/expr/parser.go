@@ -0,0 +1,278 @@
+package expr
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type span struct {
+	start int
+	end   int
+}
+
+type node interface {
+	span() span
+}
+
+type literal struct {
+	pos   span
+	value any
+}
+
+type identifier struct {
+	pos  span
+	name string
+}
+
+type member struct {
+	pos    span
+	object node
+	name   string
+}
+
+type index struct {
+	pos    span
+	object node
+	key    node
+}
+
+type call struct {
+	pos  span
+	name string
+	args []node
+	// literal patterns of matches, compiled once
+	pattern *regexp.Regexp
+}
+
+type unary struct {
+	pos     span
+	op      string
+	operand node
+}
+
+type binary struct {
+	pos   span
+	op    string
+	left  node
+	right node
+}
+
+func (n *literal) span() span    { return n.pos }
+func (n *identifier) span() span { return n.pos }
+func (n *member) span() span     { return n.pos }
+func (n *index) span() span      { return n.pos }
+func (n *call) span() span       { return n.pos }
+func (n *unary) span() span      { return n.pos }
+func (n *binary) span() span     { return n.pos }
+
+var precedence = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3,
+	"<": 4, "<=": 4, ">": 4, ">=": 4,
+	"+": 5, "-": 5,
+	"*": 6, "/": 6, "%": 6,
+}
+
+type parser struct {
+	tokens    []token
+	next      int
+	variables map[string]struct{}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.next]
+	if t.kind != tokenEOF {
+		p.next++
+	}
+	return t
+}
+
+func (p *parser) isOperator(text string) bool {
+	t := p.peek()
+	return t.kind == tokenOperator && t.text == text
+}
+
+func (p *parser) expect(text string) (token, error) {
+	if !p.isOperator(text) {
+		return token{}, p.unexpected(fmt.Sprintf("expected %q", text))
+	}
+	return p.advance(), nil
+}
+
+func (p *parser) unexpected(msg string) error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return &SyntaxError{Pos: t.pos, Msg: msg + ", found end of expression"}
+	}
+	return &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("%s, found %q", msg, t.text)}
+}
+
+// parseExpression parses binary operators by precedence climbing.
+func (p *parser) parseExpression(minPrecedence int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		prec, ok := precedence[t.text]
+		if t.kind != tokenOperator || !ok || prec < minPrecedence {
+			return left, nil
+		}
+		p.advance()
+
+		right, err := p.parseExpression(prec + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{
+			pos:   span{left.span().start, right.span().end},
+			op:    t.text,
+			left:  left,
+			right: right,
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isOperator("!") || p.isOperator("-") {
+		t := p.advance()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{pos: span{t.pos, operand.span().end}, op: t.text, operand: operand}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isOperator("."):
+			p.advance()
+			name := p.peek()
+			if name.kind != tokenIdent {
+				return nil, p.unexpected("expected field name after \".\"")
+			}
+			p.advance()
+			n = &member{pos: span{n.span().start, name.pos + len(name.text)}, object: n, name: name.text}
+
+		case p.isOperator("["):
+			p.advance()
+			key, err := p.parseExpression(1)
+			if err != nil {
+				return nil, err
+			}
+			closing, err := p.expect("]")
+			if err != nil {
+				return nil, err
+			}
+			n = &index{pos: span{n.span().start, closing.pos + 1}, object: n, key: key}
+
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber, tokenString:
+		p.advance()
+		return &literal{pos: span{t.pos, t.pos + len(t.text)}, value: t.value}, nil
+
+	case tokenIdent:
+		p.advance()
+		switch t.text {
+		case "true":
+			return &literal{pos: span{t.pos, t.pos + len(t.text)}, value: true}, nil
+		case "false":
+			return &literal{pos: span{t.pos, t.pos + len(t.text)}, value: false}, nil
+		case "null":
+			return &literal{pos: span{t.pos, t.pos + len(t.text)}, value: nil}, nil
+		}
+
+		if p.isOperator("(") {
+			return p.parseCall(t)
+		}
+
+		if p.variables != nil {
+			if _, ok := p.variables[t.text]; !ok {
+				return nil, &SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unknown variable %q", t.text)}
+			}
+		}
+		return &identifier{pos: span{t.pos, t.pos + len(t.text)}, name: t.text}, nil
+
+	case tokenOperator:
+		if t.text == "(" {
+			p.advance()
+			n, err := p.parseExpression(1)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return n, nil
+		}
+	}
+	return nil, p.unexpected("expected a value")
+}
+
+func (p *parser) parseCall(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("unknown function %q", name.text)}
+	}
+	p.advance()
+
+	var args []node
+	for !p.isOperator(")") {
+		if len(args) > 0 {
+			if _, err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpression(1)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	closing := p.advance()
+
+	if len(args) != fn.arity {
+		return nil, &SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("%s expects %d arguments, got %d", name.text, fn.arity, len(args))}
+	}
+
+	n := &call{pos: span{name.pos, closing.pos + 1}, name: name.text, args: args}
+
+	// patterns written as literals are checked up front
+	if name.text == "matches" {
+		if pattern, ok := args[1].(*literal); ok {
+			source, isString := pattern.value.(string)
+			if !isString {
+				return nil, &SyntaxError{Pos: pattern.pos.start, Msg: "matches expects a string pattern"}
+			}
+			re, err := regexp.Compile(source)
+			if err != nil {
+				return nil, &SyntaxError{Pos: pattern.pos.start, Msg: fmt.Sprintf("invalid pattern: %s", err)}
+			}
+			n.pattern = re
+		}
+	}
+	return n, nil
+}
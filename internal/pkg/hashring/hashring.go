@@ -0,0 +1,91 @@
+package hashring
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+type Ring struct {
+	virtualNodes int
+	hashes       []uint32
+	owners       map[uint32]string
+	nodes        []string
+}
+
+func New(virtualNodes int, nodes ...string) *Ring {
+	if virtualNodes < 1 {
+		virtualNodes = 1
+	}
+
+	r := &Ring{
+		virtualNodes: virtualNodes,
+		owners:       make(map[uint32]string),
+	}
+
+	sortedNodes := append([]string(nil), nodes...)
+	sort.Strings(sortedNodes)
+
+	for _, node := range sortedNodes {
+		if r.contains(node) {
+			continue
+		}
+		r.nodes = append(r.nodes, node)
+		for i := 0; i < virtualNodes; i++ {
+			hash := hashKey(node + "#" + strconv.Itoa(i))
+			if _, ok := r.owners[hash]; ok {
+				continue
+			}
+			r.owners[hash] = node
+			r.hashes = append(r.hashes, hash)
+		}
+	}
+
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+func (r *Ring) Get(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+
+	hash := hashKey(key)
+	idx := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if idx == len(r.hashes) {
+		idx = 0
+	}
+
+	return r.owners[r.hashes[idx]]
+}
+
+func (r *Ring) Nodes() []string {
+	return append([]string(nil), r.nodes...)
+}
+
+func (r *Ring) Equal(nodes []string) bool {
+	other := New(1, nodes...)
+	if len(other.nodes) != len(r.nodes) {
+		return false
+	}
+
+	for i := range r.nodes {
+		if r.nodes[i] != other.nodes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Ring) contains(node string) bool {
+	for _, n := range r.nodes {
+		if n == node {
+			return true
+		}
+	}
+	return false
+}
+
+func hashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
@@ -0,0 +1,99 @@
+package hashring_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/hashring"
+)
+
+func TestRing_Get(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		nodes []string
+		key   string
+		want  string
+	}{
+		{
+			name:  "empty ring",
+			nodes: nil,
+			key:   "a8c14eb0-0fba-4b75-a461-e4d380317ab7",
+			want:  "",
+		},
+		{
+			name:  "single node owns every key",
+			nodes: []string{"replica-a"},
+			key:   "a8c14eb0-0fba-4b75-a461-e4d380317ab7",
+			want:  "replica-a",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r := hashring.New(64, tt.nodes...)
+			if got := r.Get(tt.key); got != tt.want {
+				t.Errorf("Ring.Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRing_Distribution(t *testing.T) {
+	t.Parallel()
+
+	nodes := []string{"replica-a", "replica-b", "replica-c"}
+	r := hashring.New(128, nodes...)
+
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		counts[r.Get(fmt.Sprintf("job-%d", i))]++
+	}
+
+	for _, node := range nodes {
+		if counts[node] < 500 {
+			t.Errorf("Ring.Get() assigned %d of 3000 keys to %s, want at least 500", counts[node], node)
+		}
+	}
+}
+
+func TestRing_Rebalance(t *testing.T) {
+	t.Parallel()
+
+	before := hashring.New(128, "replica-a", "replica-b", "replica-c")
+	after := hashring.New(128, "replica-a", "replica-b")
+
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("job-%d", i)
+		owner := before.Get(key)
+		if owner == "replica-c" {
+			continue
+		}
+		if got := after.Get(key); got != owner {
+			t.Errorf("Ring.Get(%q) moved from %s to %s after an unrelated node left", key, owner, got)
+		}
+	}
+}
+
+func TestRing_Nodes(t *testing.T) {
+	t.Parallel()
+
+	r := hashring.New(8, "replica-b", "replica-a", "replica-b")
+
+	want := []string{"replica-a", "replica-b"}
+	if got := r.Nodes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Ring.Nodes() = %v, want %v", got, want)
+	}
+
+	if !r.Equal([]string{"replica-a", "replica-b"}) {
+		t.Errorf("Ring.Equal() = false, want true")
+	}
+
+	if r.Equal([]string{"replica-a"}) {
+		t.Errorf("Ring.Equal() = true, want false")
+	}
+}
@@ -3,18 +3,35 @@ package mock
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/cache"
 )
 
 type CacherStore struct {
 	Cache           map[string]string
 	CacheSet        map[string]struct{}
+	SortedSet       map[string]float64
 	SetName         string
+	Published       []string
+	Lists           map[string][]string
 	WantInsertError bool
 	WantDeleteError bool
 	WantGetError    bool
+	// number of Subscribe calls that fail before one succeeds
+	SubscribeFailures int
+
+	subscribers map[string][]chan string
+
+	// guards every field, the automator calls the store from the run,
+	// reconcile and event goroutines at once
+	mu sync.Mutex
 }
 
 func (c *CacherStore) InsertData(ctx context.Context, key, data string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.WantInsertError {
 		return fmt.Errorf("insert error")
 	}
@@ -23,14 +40,25 @@ func (c *CacherStore) InsertData(ctx context.Context, key, data string) error {
 }
 
 func (c *CacherStore) GetData(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	data, ok := c.Cache[key]
 	if !ok {
-		return "", fmt.Errorf("data not found")
+		return "", &cache.NotFoundError{}
 	}
 	return data, nil
 }
 
+func (c *CacherStore) LookupData(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.Cache[key]
+	return data, ok, nil
+}
+
 func (c *CacherStore) DeleteData(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.WantDeleteError {
 		return fmt.Errorf("delete error")
 	}
@@ -40,11 +68,13 @@ func (c *CacherStore) DeleteData(ctx context.Context, key string) error {
 }
 
 func (c *CacherStore) GetSet(ctx context.Context, key string) (map[string]struct{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if key != c.SetName {
 		return nil, fmt.Errorf("set not found")
 	}
 
-	if c.CacheSet != nil {
+	if c.CacheSet == nil {
 		return nil, fmt.Errorf("nil cache set")
 	}
 
@@ -52,11 +82,18 @@ func (c *CacherStore) GetSet(ctx context.Context, key string) (map[string]struct
 		return nil, fmt.Errorf("get error")
 	}
 
-	return c.CacheSet, nil
+	// a copy, callers range over it after the lock is released
+	set := make(map[string]struct{}, len(c.CacheSet))
+	for k := range c.CacheSet {
+		set[k] = struct{}{}
+	}
+	return set, nil
 
 }
 
 func (c *CacherStore) DeleteSet(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	if key != c.SetName {
 		return fmt.Errorf("error deleting set")
@@ -68,6 +105,8 @@ func (c *CacherStore) DeleteSet(ctx context.Context, key string) error {
 }
 
 func (c *CacherStore) DeleteFromSet(ctx context.Context, setName string, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if setName != c.SetName {
 		return fmt.Errorf("setName not found")
 	}
@@ -83,6 +122,8 @@ func (c *CacherStore) DeleteFromSet(ctx context.Context, setName string, keys ..
 }
 
 func (c *CacherStore) UpdateSet(ctx context.Context, setName string, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
 	if setName != c.SetName {
 		return fmt.Errorf("setName not found")
@@ -97,3 +138,181 @@ func (c *CacherStore) UpdateSet(ctx context.Context, setName string, keys ...str
 	}
 	return nil
 }
+
+func (c *CacherStore) UpdateSortedSet(ctx context.Context, setName, member string, score float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.WantInsertError {
+		return fmt.Errorf("insert error")
+	}
+
+	if c.SortedSet == nil {
+		c.SortedSet = make(map[string]float64)
+	}
+	c.SortedSet[member] = score
+	return nil
+}
+
+func (c *CacherStore) GetSortedSetByScore(ctx context.Context, setName string, min, max float64) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.WantGetError {
+		return nil, fmt.Errorf("get error")
+	}
+
+	members := make([]string, 0)
+	for member, score := range c.SortedSet {
+		if score >= min && score <= max {
+			members = append(members, member)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+func (c *CacherStore) DeleteFromSortedSet(ctx context.Context, setName string, members ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.WantDeleteError {
+		return fmt.Errorf("delete error")
+	}
+
+	for _, m := range members {
+		delete(c.SortedSet, m)
+	}
+	return nil
+}
+
+func (c *CacherStore) DeleteFromSortedSetByScore(ctx context.Context, setName string, min, max float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.WantDeleteError {
+		return fmt.Errorf("delete error")
+	}
+
+	for member, score := range c.SortedSet {
+		if score >= min && score <= max {
+			delete(c.SortedSet, member)
+		}
+	}
+	return nil
+}
+
+func (c *CacherStore) Publish(ctx context.Context, channel, message string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Published = append(c.Published, message)
+	return nil
+}
+
+func (c *CacherStore) Subscribe(ctx context.Context, channel string) (<-chan string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.SubscribeFailures > 0 {
+		c.SubscribeFailures--
+		return nil, fmt.Errorf("subscribe error")
+	}
+
+	if c.subscribers == nil {
+		c.subscribers = make(map[string][]chan string)
+	}
+	// Deliver sends to inbox, which is never closed, so a delivery racing
+	// with an unsubscribe cannot panic
+	inbox := make(chan string)
+	c.subscribers[channel] = append(c.subscribers[channel], inbox)
+
+	messages := make(chan string)
+	go func() {
+		defer close(messages)
+		defer c.unsubscribe(channel, inbox)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message := <-inbox:
+				select {
+				case messages <- message:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return messages, nil
+}
+
+func (c *CacherStore) unsubscribe(channel string, inbox chan string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	subscribers := c.subscribers[channel]
+	for i, subscriber := range subscribers {
+		if subscriber == inbox {
+			c.subscribers[channel] = append(subscribers[:i:i], subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// Subscribed reports whether anything is subscribed to channel.
+func (c *CacherStore) Subscribed(channel string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.subscribers[channel]) > 0
+}
+
+// Deliver sends a message to the current subscribers of channel, as if it
+// had been published by another replica.
+func (c *CacherStore) Deliver(ctx context.Context, channel, message string) {
+	c.mu.Lock()
+	subscribers := append([]chan string(nil), c.subscribers[channel]...)
+	c.mu.Unlock()
+
+	for _, subscriber := range subscribers {
+		select {
+		case subscriber <- message:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *CacherStore) PushToList(ctx context.Context, key, data string, maxLen int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.WantInsertError {
+		return fmt.Errorf("insert error")
+	}
+
+	if c.Lists == nil {
+		c.Lists = make(map[string][]string)
+	}
+
+	list := append([]string{data}, c.Lists[key]...)
+	if maxLen > 0 && int64(len(list)) > maxLen {
+		list = list[:maxLen]
+	}
+	c.Lists[key] = list
+	return nil
+}
+
+func (c *CacherStore) GetList(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.WantGetError {
+		return nil, fmt.Errorf("get error")
+	}
+
+	list := c.Lists[key]
+	if stop < 0 || stop >= int64(len(list)) {
+		stop = int64(len(list)) - 1
+	}
+	if start > stop {
+		return []string{}, nil
+	}
+	return append([]string(nil), list[start:stop+1]...), nil
+}
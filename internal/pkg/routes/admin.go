@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+)
+
+type AdminRoute struct {
+	logger       *zap.SugaredLogger
+	jobAutomator *automators.Automator
+}
+
+func NewAdminRoute(logger *zap.SugaredLogger, jobAutomator *automators.Automator) *AdminRoute {
+	return &AdminRoute{
+		logger:       logger,
+		jobAutomator: jobAutomator,
+	}
+}
+
+func (a *AdminRoute) GetLastReconcile(c *gin.Context) {
+	result := a.jobAutomator.LastReconcile()
+	if result == nil {
+		c.JSON(http.StatusNotFound, GenericResponse{Message: "no reconcile has run yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,43 @@
+package routes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+
+	"github.com/jboakyedonkor/ping-app/internal/pkg/automators"
+	"github.com/jboakyedonkor/ping-app/internal/pkg/cache"
+)
+
+type HeartbeatRoute struct {
+	logger       *zap.SugaredLogger
+	jobAutomator *automators.Automator
+}
+
+func NewHeartbeatRoute(logger *zap.SugaredLogger, jobAutomator *automators.Automator) *HeartbeatRoute {
+	return &HeartbeatRoute{
+		logger:       logger,
+		jobAutomator: jobAutomator,
+	}
+}
+
+func (h *HeartbeatRoute) RecordHeartbeat(c *gin.Context) {
+	token := c.Param("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, GenericResponse{Message: "no heartbeat token specified"})
+		return
+	}
+
+	if err := h.jobAutomator.RecordHeartbeat(c.Request.Context(), token); err != nil {
+		if _, ok := err.(*cache.NotFoundError); ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, GenericResponse{Message: "error recording heartbeat"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
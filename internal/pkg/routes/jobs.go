@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"strconv"
 
 	"go.uber.org/zap"
 
@@ -25,6 +26,10 @@ func (j *JobRoute) CreateJob(c *gin.Context) {
 
 	uid, err := j.jobAutomator.CreateNewJob(c.Request.Context(), newJob)
 	if err != nil {
+		if validationErr, ok := err.(*automators.ValidationError); ok {
+			c.JSON(http.StatusBadRequest, GenericResponse{Message: validationErr.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, GenericResponse{Message: "error creating new job"})
 		return
 	}
@@ -58,6 +63,71 @@ func (j *JobRoute) GetJobConfig(c *gin.Context) {
 	c.JSON(http.StatusOK, config)
 }
 
+func (j *JobRoute) UpdateJob(c *gin.Context) {
+	id, ok := c.Params.Get("id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, "no job uid specified")
+		return
+	}
+
+	jobUUID, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GenericResponse{Message: "incorrect request body"})
+		return
+	}
+
+	var job automators.JobConfig
+	if err := c.BindJSON(&job); err != nil {
+		c.JSON(http.StatusBadRequest, GenericResponse{Message: "incorrect request body"})
+		return
+	}
+
+	if err := j.jobAutomator.UpdateJob(c.Request.Context(), jobUUID, job); err != nil {
+		if _, ok := err.(*cache.NotFoundError); ok {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		if validationErr, ok := err.(*automators.ValidationError); ok {
+			c.JSON(http.StatusBadRequest, GenericResponse{Message: validationErr.Error()})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, GenericResponse{Message: "error updating job"})
+		return
+	}
+
+	c.JSON(http.StatusOK, GenericResponse{UID: jobUUID.String()})
+}
+
+func (j *JobRoute) GetJobResults(c *gin.Context) {
+	id, ok := c.Params.Get("id")
+	if !ok {
+		c.JSON(http.StatusBadRequest, "no job uid specified")
+		return
+	}
+
+	jobUUID, err := uuid.Parse(id)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GenericResponse{Message: "incorrect request body"})
+		return
+	}
+
+	limit, err := strconv.ParseInt(c.DefaultQuery("limit", "0"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, GenericResponse{Message: "incorrect limit"})
+		return
+	}
+
+	results, err := j.jobAutomator.GetJobResults(c.Request.Context(), jobUUID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, GenericResponse{Message: "internal server error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
 func (j *JobRoute) DeleteJob(c *gin.Context) {
 	id, ok := c.Params.Get("id")
 	if !ok {
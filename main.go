@@ -6,12 +6,15 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-co-op/gocron"
 	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
@@ -20,12 +23,21 @@ import (
 	"github.com/jboakyedonkor/ping-app/internal/pkg/routes"
 )
 
+const (
+	shutdownTimeout = 30 * time.Second
+)
+
 type envConfig struct {
-	loggingMode string
-	redisHost   string
-	redisPort   string
-	secretKey   string
-	appPort     string
+	loggingMode         string
+	redisHost           string
+	redisPort           string
+	secretKey           string
+	appPort             string
+	replicaID           string
+	reconcileInterval   time.Duration
+	shutdownGracePeriod time.Duration
+	transportSettings   automators.TransportSettings
+	proxyURL            string
 }
 
 func main() {
@@ -37,9 +49,18 @@ func main() {
 	scheduler := gocron.NewScheduler(time.UTC)
 
 	redisCache := cache.NewCache(getRedisClient(config), logger)
-	automator := automators.NewAutomator(redisCache, []byte(config.secretKey), scheduler, logger)
+	automator := automators.NewAutomator(redisCache, []byte(config.secretKey), scheduler, logger,
+		automators.WithReplicaID(config.replicaID),
+		automators.WithReconcileInterval(config.reconcileInterval),
+		automators.WithShutdownGracePeriod(config.shutdownGracePeriod),
+		automators.WithTransportSettings(config.transportSettings),
+		automators.WithMetricsRegisterer(prometheus.DefaultRegisterer),
+		automators.WithProxy(config.proxyURL),
+	)
 
 	jobRoute := routes.NewJobRoute(logger, automator)
+	adminRoute := routes.NewAdminRoute(logger, automator)
+	heartbeatRoute := routes.NewHeartbeatRoute(logger, automator)
 	app := gin.New()
 
 	app.Use(func(c *gin.Context) {
@@ -57,16 +78,26 @@ func main() {
 
 	jobGroup := app.Group("/jobs")
 	jobGroup.DELETE("/:id", jobRoute.DeleteJob)
+	jobGroup.PUT("/:id", jobRoute.UpdateJob)
 	jobGroup.GET("/:id/config", jobRoute.GetJobConfig)
+	jobGroup.GET("/:id/results", jobRoute.GetJobResults)
 	jobGroup.GET("", jobRoute.GetJobs)
 	jobGroup.POST("", jobRoute.CreateJob)
 
-	scheduler.StartAsync()
+	adminGroup := app.Group("/admin")
+	adminGroup.GET("/reconcile", adminRoute.GetLastReconcile)
+
+	app.POST("/heartbeat/:token", heartbeatRoute.RecordHeartbeat)
+	app.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	port := config.appPort
 	if port == "" {
 		port = "8080"
 	}
-	go automator.ReconcileJobs()
+
+	if err := automator.Start(context.Background()); err != nil {
+		logger.Fatalf("error starting automator: %s", err)
+	}
 
 	logger.Infof("listening on port %s", port)
 	addr := fmt.Sprintf(":%s", port)
@@ -84,11 +115,25 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	if err := srv.Shutdown(context.Background()); err != nil {
-		logger.Fatalf("error shutting down server: %s", err)
-	}
 	logger.Info("shutting down server")
 
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Errorf("error shutting down server: %s", err)
+	}
+
+	// the automator bounds the drain by its grace period, this deadline only
+	// guards against checks that ignore cancellation
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), config.shutdownGracePeriod+shutdownTimeout)
+	defer stopCancel()
+
+	if err := automator.Stop(stopCtx); err != nil {
+		logger.Errorf("error stopping automator: %s", err)
+	}
+	logger.Info("server stopped")
+
 }
 
 func getLogger(config envConfig) *zap.SugaredLogger {
@@ -139,9 +184,57 @@ func getRedisClient(config envConfig) *redis.Client {
 
 func getEnvConfig() envConfig {
 	return envConfig{
-		loggingMode: os.Getenv("MODE"),
-		redisHost:   os.Getenv("REDIS_HOST"),
-		redisPort:   os.Getenv("REDIS_PORT"),
-		secretKey:   os.Getenv("SECRET_KEY"),
+		loggingMode:       os.Getenv("MODE"),
+		redisHost:         os.Getenv("REDIS_HOST"),
+		redisPort:         os.Getenv("REDIS_PORT"),
+		secretKey:         os.Getenv("SECRET_KEY"),
+		replicaID:         os.Getenv("REPLICA_ID"),
+		proxyURL:          getEnvProxyURL("PROXY_URL"),
+		reconcileInterval: getEnvDuration("RECONCILE_INTERVAL"),
+
+		shutdownGracePeriod: getEnvDuration("SHUTDOWN_GRACE_PERIOD"),
+		transportSettings: automators.TransportSettings{
+			MaxIdleConns:        getEnvInt("HTTP_MAX_IDLE_CONNS"),
+			MaxIdleConnsPerHost: getEnvInt("HTTP_MAX_IDLE_CONNS_PER_HOST"),
+			IdleConnTimeout:     getEnvDuration("HTTP_IDLE_CONN_TIMEOUT"),
+		},
+	}
+}
+
+func getEnvDuration(key string) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid duration for %s: %s", key, err))
+	}
+	return duration
+}
+
+func getEnvProxyURL(key string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return ""
+	}
+
+	if err := automators.ValidateProxyURL(value); err != nil {
+		panic(fmt.Sprintf("invalid %s: %s", key, err))
+	}
+	return value
+}
+
+func getEnvInt(key string) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("invalid integer for %s: %s", key, err))
 	}
+	return n
 }